@@ -0,0 +1,13 @@
+package app
+
+import "time"
+
+// Comment represents a single comment to the article
+type Comment struct {
+	Id        int       `json:"id"`
+	ArticleId int       `json:"-"`
+	Body      string    `json:"body"`
+	Created   time.Time `json:"createdAt"`
+	Updated   time.Time `json:"updatedAt"`
+	Author    Profile   `json:"author"`
+}
@@ -2,6 +2,8 @@ package app
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 )
 
 type ErrorType int
@@ -10,6 +12,10 @@ const (
 	ErrorTypeInternal = iota
 	ErrorTypeService
 	ErrorTypeAuth
+	ErrorTypeConflict
+	ErrorTypeNotFound
+	ErrorTypeForbidden
+	ErrorTypeValidation
 )
 
 func (et ErrorType) String() string {
@@ -20,6 +26,14 @@ func (et ErrorType) String() string {
 		return "ErrorTypeService"
 	case ErrorTypeAuth:
 		return "ErrorTypeAuth"
+	case ErrorTypeConflict:
+		return "ErrorTypeConflict"
+	case ErrorTypeNotFound:
+		return "ErrorTypeNotFound"
+	case ErrorTypeForbidden:
+		return "ErrorTypeForbidden"
+	case ErrorTypeValidation:
+		return "ErrorTypeValidation"
 	default:
 		return fmt.Sprintf("%d", et)
 	}
@@ -49,3 +63,56 @@ func ServiceError(err error) Error {
 func AuthError(err error) Error {
 	return Error{ErrorTypeAuth, err}
 }
+
+func ConflictError(err error) Error {
+	return Error{ErrorTypeConflict, err}
+}
+
+func NotFoundError(err error) Error {
+	return Error{ErrorTypeNotFound, err}
+}
+
+func ForbiddenError(err error) Error {
+	return Error{ErrorTypeForbidden, err}
+}
+
+func ValidationError(err error) Error {
+	return Error{ErrorTypeValidation, err}
+}
+
+// FieldErrors is a validation error that holds problems of the request keyed
+// by field name, e.g. {"title": ["is required"]}
+type FieldErrors map[string][]string
+
+// Add appends problem to the list of field problems
+func (fe FieldErrors) Add(field, problem string) {
+	fe[field] = append(fe[field], problem)
+}
+
+// Err returns nil if there are no problems or the field errors otherwise.
+// It's used to return field errors from validators without the nil interface
+// pitfall.
+func (fe FieldErrors) Err() error {
+	if len(fe) == 0 {
+		return nil
+	}
+
+	return fe
+}
+
+func (fe FieldErrors) Error() string {
+	fields := make([]string, 0, len(fe))
+	for field := range fe {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var problems []string
+	for _, field := range fields {
+		for _, problem := range fe[field] {
+			problems = append(problems, field+" "+problem)
+		}
+	}
+
+	return strings.Join(problems, ", ")
+}
@@ -0,0 +1,12 @@
+package app
+
+import "time"
+
+// RefreshToken describes the refresh token issued to the user. Token itself
+// is never stored, only its hash is.
+type RefreshToken struct {
+	Hash    string
+	UserId  int
+	Created time.Time
+	Expires time.Time
+}
@@ -21,6 +21,10 @@ type User struct {
 	// Token is JWT returned by user register, login and update
 	Token string `json:"token,omitempty"`
 
+	// RefreshToken is returned by user register, login and token refresh. It's
+	// exchanged for a new JWT when the current one expires.
+	RefreshToken string `json:"refreshToken,omitempty"`
+
 	PasswordHash string `json:"-"`
 }
 
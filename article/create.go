@@ -1,6 +1,7 @@
 package article
 
 import (
+	"context"
 	"time"
 
 	"github.com/dchest/uniuri"
@@ -20,30 +21,34 @@ type CreateRequest struct {
 }
 
 type ArticleRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Body        string `json:"body"`
-	// TagList []Tag `json:"tagList"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Body        string   `json:"body"`
+	TagList     []string `json:"tagList"`
 }
 
+// Validate checks the request and returns app.FieldErrors with all of the
+// problems found
 func (r *CreateRequest) Validate() error {
+	errs := app.FieldErrors{}
+
 	if empty.MatchString(r.Article.Title) {
-		return errorValidationTitleIsRequired
+		errs.Add("title", "is required")
 	}
 
 	if empty.MatchString(r.Article.Body) {
-		return errorValidationBodyIsRequired
+		errs.Add("body", "is required")
 	}
 
-	return nil
+	return errs.Err()
 }
 
 // Create creates new article in the articles store
-func (s *Service) Create(req *CreateRequest, author *app.Profile) (*app.Article, error) {
+func (s *Service) Create(ctx context.Context, req *CreateRequest, author *app.Profile) (*app.Article, error) {
 	// Validate request
 	err := req.Validate()
 	if err != nil {
-		return nil, app.ServiceError(err)
+		return nil, app.ValidationError(err)
 	}
 
 	article := &app.Article{
@@ -51,6 +56,7 @@ func (s *Service) Create(req *CreateRequest, author *app.Profile) (*app.Article,
 		Description: req.Article.Description,
 		Body:        req.Article.Body,
 		Author:      *author,
+		TagList:     normalizeTags(req.Article.TagList),
 		Created:     time.Now(),
 		Updated:     time.Now(),
 
@@ -62,7 +68,7 @@ func (s *Service) Create(req *CreateRequest, author *app.Profile) (*app.Article,
 	// identified by slug which is randomly generated
 
 	// Persist article in the store
-	err = s.store.CreateArticle(article)
+	err = s.store.CreateArticle(ctx, article)
 	if err != nil {
 		return nil, app.InternalError(errors.Wrap(err, "failed to create article"))
 	}
@@ -1,9 +1,13 @@
 package article
 
 import (
+	"context"
 	"errors"
+	"reflect"
 	"testing"
 
+	"github.com/go-test/deep"
+
 	"github.com/dzeban/conduit/app"
 	"github.com/dzeban/conduit/mock"
 )
@@ -20,7 +24,7 @@ func TestValidate(t *testing.T) {
 					Body:  "",
 				},
 			},
-			errorValidationTitleIsRequired,
+			app.FieldErrors{"title": {"is required"}, "body": {"is required"}},
 		},
 		{
 			CreateRequest{
@@ -29,7 +33,7 @@ func TestValidate(t *testing.T) {
 					Body:  "x",
 				},
 			},
-			errorValidationTitleIsRequired,
+			app.FieldErrors{"title": {"is required"}},
 		},
 		{
 			CreateRequest{
@@ -38,7 +42,7 @@ func TestValidate(t *testing.T) {
 					Body:  "",
 				},
 			},
-			errorValidationBodyIsRequired,
+			app.FieldErrors{"body": {"is required"}},
 		},
 		{
 			CreateRequest{
@@ -48,7 +52,7 @@ func TestValidate(t *testing.T) {
 					Description: "x",
 				},
 			},
-			errorValidationTitleIsRequired,
+			app.FieldErrors{"title": {"is required"}, "body": {"is required"}},
 		},
 		{
 			CreateRequest{
@@ -63,7 +67,7 @@ func TestValidate(t *testing.T) {
 
 	for _, tt := range tests {
 		err := tt.req.Validate()
-		if err != tt.err {
+		if !reflect.DeepEqual(err, tt.err) {
 			t.Errorf("Validate(%+v): invalid error, expected '%v', got '%v'", tt.req, tt.err, err)
 		}
 	}
@@ -80,8 +84,8 @@ func TestCreate(t *testing.T) {
 		{
 			"EmptyValidation",
 			&CreateRequest{},
-			app.ErrorTypeService,
-			errorValidationTitleIsRequired,
+			app.ErrorTypeValidation,
+			app.FieldErrors{"title": {"is required"}, "body": {"is required"}},
 		},
 		// NOTE: We should check the case when existing article is created but
 		// we can't do so because article uniquiness is checked by slug which is
@@ -109,7 +113,7 @@ func TestCreate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := s.Create(tt.req, &mock.Author)
+			_, err := s.Create(context.Background(), tt.req, &mock.Author)
 			if err != nil {
 				// Check error
 				var e app.Error
@@ -127,7 +131,7 @@ func TestCreate(t *testing.T) {
 
 				// Check error value
 				if tt.err != nil {
-					if e.Err != tt.err {
+					if !reflect.DeepEqual(e.Err, tt.err) {
 						t.Errorf("Create(%v): invalid error value: expected %v, got %v", tt.req, e.Err, tt.err)
 						return
 					}
@@ -137,3 +141,31 @@ func TestCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateTags(t *testing.T) {
+	s := NewService(mock.NewArticleStore(), mock.NewProfilesStore())
+
+	a, err := s.Create(context.Background(), &CreateRequest{
+		ArticleRequest{
+			Title:   "tagged",
+			Body:    "tagged",
+			TagList: []string{"go", " go ", "", "web"},
+		},
+	}, &mock.Author)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(a.TagList, []string{"go", "web"}); diff != nil {
+		t.Error(diff)
+	}
+
+	stored, err := s.Get(context.Background(), a.Slug, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(stored.TagList, a.TagList); diff != nil {
+		t.Error(diff)
+	}
+}
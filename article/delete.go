@@ -1,28 +1,30 @@
 package article
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 
 	"github.com/dzeban/conduit/app"
 )
 
-func (s *Service) Delete(slug string, author *app.Profile) error {
+func (s *Service) Delete(ctx context.Context, slug string, author *app.Profile) error {
 	// Find article to get its id and check author
-	a, err := s.store.GetArticle(slug)
+	a, err := s.store.GetArticle(ctx, slug, author)
 	if err != nil {
 		return app.InternalError(errors.Wrap(err, "failed to get article for delete"))
 	}
 
 	if a == nil {
-		return app.ServiceError(errorArticleNotFound)
+		return app.NotFoundError(errorArticleNotFound)
 	}
 
 	// Check that article belongs to author
 	if a.Author.Id != author.Id {
-		return app.ServiceError(errorArticleDeleteForbidden)
+		return app.ForbiddenError(errorArticleDeleteForbidden)
 	}
 
-	err = s.store.DeleteArticle(a.Id)
+	err = s.store.DeleteArticle(ctx, a.Id)
 	if err != nil {
 		return app.InternalError(errors.Wrap(err, "article delete failed"))
 	}
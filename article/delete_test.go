@@ -1,6 +1,7 @@
 package article
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -41,7 +42,7 @@ func TestDelete(t *testing.T) {
 	s := NewService(mock.NewArticleStore(), mock.NewProfilesStore())
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := s.Delete(tt.slug, tt.author)
+			err := s.Delete(context.Background(), tt.slug, tt.author)
 			if tt.err != nil {
 				if !errors.Is(err, tt.err) {
 					t.Errorf("Delete(%v, %v): invalid error: expected '%v', got '%v'", tt.slug, tt.author, tt.err, err)
@@ -54,12 +55,12 @@ func TestDelete(t *testing.T) {
 func TestDeleteForReal(t *testing.T) {
 	s := NewService(mock.NewArticleStore(), mock.NewProfilesStore())
 
-	err := s.Delete(mock.ArticleValid.Slug, &mock.Author)
+	err := s.Delete(context.Background(), mock.ArticleValid.Slug, &mock.Author)
 	if err != nil {
 		t.Errorf("Delete(%v, %v): unexpected error '%v'", mock.ArticleValid.Slug, mock.Author, err)
 	}
 
-	_, err = s.Get(mock.ArticleValid.Slug)
+	_, err = s.Get(context.Background(), mock.ArticleValid.Slug, nil)
 	if !errors.Is(err, errorArticleNotFound) {
 		t.Errorf("Expected article not found after delete, got err '%v'", err)
 	}
@@ -0,0 +1,61 @@
+package article
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+)
+
+// Favorite marks article found by slug as favorited by user.
+// Favoriting already favorited article is not an error.
+// Returns the article with updated favorites info.
+func (s *Service) Favorite(ctx context.Context, slug string, user *app.Profile) (*app.Article, error) {
+	a, err := s.store.GetArticle(ctx, slug, user)
+	if err != nil {
+		return nil, app.InternalError(errors.Wrap(err, "failed to get article for favorite"))
+	}
+
+	if a == nil {
+		return nil, app.NotFoundError(errorArticleNotFound)
+	}
+
+	err = s.store.FavoriteArticle(ctx, user, a)
+	if err != nil {
+		return nil, app.InternalError(errors.Wrap(err, "failed to favorite article"))
+	}
+
+	a, err = s.store.GetArticle(ctx, slug, user)
+	if err != nil {
+		return nil, app.InternalError(errors.Wrap(err, "failed to get article after favorite"))
+	}
+
+	return a, nil
+}
+
+// Unfavorite removes article found by slug from user favorites.
+// Unfavoriting article that is not favorited is not an error.
+// Returns the article with updated favorites info.
+func (s *Service) Unfavorite(ctx context.Context, slug string, user *app.Profile) (*app.Article, error) {
+	a, err := s.store.GetArticle(ctx, slug, user)
+	if err != nil {
+		return nil, app.InternalError(errors.Wrap(err, "failed to get article for unfavorite"))
+	}
+
+	if a == nil {
+		return nil, app.NotFoundError(errorArticleNotFound)
+	}
+
+	err = s.store.UnfavoriteArticle(ctx, user, a)
+	if err != nil {
+		return nil, app.InternalError(errors.Wrap(err, "failed to unfavorite article"))
+	}
+
+	a, err = s.store.GetArticle(ctx, slug, user)
+	if err != nil {
+		return nil, app.InternalError(errors.Wrap(err, "failed to get article after unfavorite"))
+	}
+
+	return a, nil
+}
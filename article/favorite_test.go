@@ -0,0 +1,95 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dzeban/conduit/app"
+	"github.com/dzeban/conduit/mock"
+)
+
+func TestFavorite(t *testing.T) {
+	s := NewService(mock.NewArticleStore(), mock.NewProfilesStore())
+
+	// Favorite twice to check that it's idempotent
+	for i := 0; i < 2; i++ {
+		a, err := s.Favorite(context.Background(), mock.ArticleValid.Slug, &mock.Profile2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !a.Favorited || a.FavoritesCount != 1 {
+			t.Errorf("Favorite(%v): expected favorited article with 1 favorite, got favorited %v, count %v", mock.ArticleValid.Slug, a.Favorited, a.FavoritesCount)
+		}
+	}
+
+	// Other users see the count but not the favorited flag
+	a, err := s.Get(context.Background(), mock.ArticleValid.Slug, &mock.Profile1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Favorited || a.FavoritesCount != 1 {
+		t.Errorf("Get(%v): expected not favorited article with 1 favorite, got favorited %v, count %v", mock.ArticleValid.Slug, a.Favorited, a.FavoritesCount)
+	}
+
+	a, err = s.Unfavorite(context.Background(), mock.ArticleValid.Slug, &mock.Profile2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Favorited || a.FavoritesCount != 0 {
+		t.Errorf("Unfavorite(%v): expected article without favorites, got favorited %v, count %v", mock.ArticleValid.Slug, a.Favorited, a.FavoritesCount)
+	}
+}
+
+func TestFavoriteNotFound(t *testing.T) {
+	s := NewService(mock.NewArticleStore(), mock.NewProfilesStore())
+
+	_, err := s.Favorite(context.Background(), "absent", &mock.Profile2)
+	if !errors.Is(err, errorArticleNotFound) {
+		t.Errorf("invalid error, expected '%v', got '%v'", errorArticleNotFound, err)
+	}
+
+	_, err = s.Unfavorite(context.Background(), "absent", &mock.Profile2)
+	if !errors.Is(err, errorArticleNotFound) {
+		t.Errorf("invalid error, expected '%v', got '%v'", errorArticleNotFound, err)
+	}
+}
+
+func TestListFavorited(t *testing.T) {
+	store := newTestStore(t)
+	s := NewService(store, store)
+
+	_, err := s.Favorite(context.Background(), store.valid.Slug, app.ProfileFromUser(store.reader))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filter := app.NewArticleListFilter()
+	filter.Favorited = &app.Profile{Name: store.reader.Name}
+	articles, count, err := s.List(context.Background(), &filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(articles) != 1 || count != 1 || articles[0].Id != store.valid.Id {
+		t.Errorf("List(favorited=%v): expected only article %v, got %+v (count %v)", store.reader.Name, store.valid.Id, articles, count)
+	}
+}
+
+func TestListUnknownFavorited(t *testing.T) {
+	s := NewService(mock.NewArticleStore(), mock.NewProfilesStore())
+
+	filter := app.NewArticleListFilter()
+	filter.Favorited = &app.Profile{Name: "absent"}
+	articles, count, err := s.List(context.Background(), &filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(articles) != 0 || count != 0 {
+		t.Errorf("List(favorited=absent): expected no articles, got %v articles, count %v", len(articles), count)
+	}
+}
@@ -1,14 +1,20 @@
 package article
 
-import "github.com/dzeban/conduit/app"
+import (
+	"context"
 
-func (s *Service) Get(slug string) (*app.Article, error) {
-	a, err := s.store.GetArticle(slug)
+	"github.com/dzeban/conduit/app"
+)
+
+// Get returns article by slug. Viewer is optional and used to fill
+// viewer-specific fields like favorited.
+func (s *Service) Get(ctx context.Context, slug string, viewer *app.Profile) (*app.Article, error) {
+	a, err := s.store.GetArticle(ctx, slug, viewer)
 
 	// Service store will return (nil, nil) when article not found.
 	// Here, we set application level error to avoid nil dereference.
 	if a == nil && err == nil {
-		return nil, app.ServiceError(errorArticleNotFound)
+		return nil, app.NotFoundError(errorArticleNotFound)
 	}
 
 	if err != nil {
@@ -0,0 +1,28 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dzeban/conduit/app"
+	"github.com/dzeban/conduit/mock"
+)
+
+func TestGetCancelled(t *testing.T) {
+	s := NewService(mock.NewArticleStore(), mock.NewProfilesStore())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.Get(ctx, mock.ArticleValid.Slug, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Get with cancelled context: expected '%v', got '%v'", context.Canceled, err)
+	}
+
+	filter := app.NewArticleListFilter()
+	_, _, err = s.List(ctx, &filter)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("List with cancelled context: expected '%v', got '%v'", context.Canceled, err)
+	}
+}
@@ -3,7 +3,9 @@ package article
 import (
 	"encoding/json"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi"
 	"github.com/pkg/errors"
@@ -16,28 +18,34 @@ import (
 type Server struct {
 	router  *chi.Mux
 	service *Service
-	secret  []byte
+	signer  *jwt.Signer
 }
 
-func NewHTTP(store Store, profilesStore ProfilesStore, secret []byte) (*Server, error) {
+func NewHTTP(store Store, profilesStore ProfilesStore, signer *jwt.Signer, authOpts ...jwt.AuthOption) (*Server, error) {
 	s := &Server{
 		router:  chi.NewRouter(),
 		service: NewService(store, profilesStore),
-		secret:  secret,
+		signer:  signer,
 	}
 
-	// Unauthenticated endpoints
-	s.router.Get("/", transport.WithError(s.HandleList))
-	s.router.Get("/{slug}", transport.WithError(s.HandleGet))
+	// Endpoints with optional JWT auth
+	s.router.Group(func(r chi.Router) {
+		r.Use(jwt.Auth(s.signer, jwt.AuthTypeOptional, authOpts...))
+
+		r.Get("/", transport.WithError(s.HandleList))
+		r.Get("/{slug}", transport.WithError(s.HandleGet))
+	})
 
 	// Endpoints protected by JWT auth
 	s.router.Group(func(r chi.Router) {
-		r.Use(jwt.Auth(s.secret, jwt.AuthTypeRequired))
+		r.Use(jwt.Auth(s.signer, jwt.AuthTypeRequired, authOpts...))
 
 		r.Post("/", transport.WithError(s.HandleCreate))
 		r.Get("/feed", transport.WithError(s.HandleFeed))
 		r.Put("/{slug}", transport.WithError(s.HandleUpdate))
 		r.Delete("/{slug}", transport.WithError(s.HandleDelete))
+		r.Post("/{slug}/favorite", transport.WithError(s.HandleFavorite))
+		r.Delete("/{slug}/favorite", transport.WithError(s.HandleUnfavorite))
 	})
 
 	return s, nil
@@ -55,12 +63,89 @@ type ResponseSingle struct {
 type ResponseMulti struct {
 	Articles []*app.Article `json:"articles"`
 	Count    int            `json:"articlesCount"`
+
+	// NextCursor is passed as "cursor" query param to get the next page.
+	// It's empty when there are no more articles.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// parsePagination fills limit, offset and cursor in filter from query params
+func parsePagination(params url.Values, filter *app.ArticleListFilter) error {
+	if limit := params.Get("limit"); limit != "" {
+		l, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			return app.ValidationError(errorArticleInvalidLimit)
+		}
+		filter.Limit = l
+	}
+
+	if offset := params.Get("offset"); offset != "" {
+		o, err := strconv.ParseUint(offset, 10, 64)
+		if err != nil {
+			return app.ValidationError(errorArticleInvalidOffset)
+		}
+		filter.Offset = o
+	}
+
+	if cursor := params.Get("cursor"); cursor != "" {
+		c, err := app.ParseArticleCursor(cursor)
+		if err != nil {
+			return app.ValidationError(errorArticleInvalidCursor)
+		}
+		filter.After = c
+	}
+
+	return nil
+}
+
+// etag returns ETag header value for the article. It's the article version
+// that is passed back by clients in If-Match header on update.
+func etag(a *app.Article) string {
+	return strconv.Quote(strconv.Itoa(a.Version))
+}
+
+// parseETag returns article version from If-Match header value. Empty value
+// and "*" mean any version and are returned as zero.
+func parseETag(s string) (int, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "W/")
+	if s == "" || s == "*" {
+		return 0, nil
+	}
+
+	unquoted, err := strconv.Unquote(s)
+	if err != nil {
+		return 0, err
+	}
+
+	version, err := strconv.Atoi(unquoted)
+	if err != nil {
+		return 0, err
+	}
+
+	if version <= 0 {
+		return 0, errors.New("version must be positive")
+	}
+
+	return version, nil
+}
+
+// nextCursor returns the cursor for the page following the articles page.
+// Page that is not full is the last one so there is no next cursor for it.
+// Search results are paginated with offset only.
+func nextCursor(articles []*app.Article, filter *app.ArticleListFilter) string {
+	if len(articles) == 0 || uint64(len(articles)) < filter.Limit || filter.Query != "" {
+		return ""
+	}
+
+	return app.NewArticleCursor(articles[len(articles)-1]).String()
 }
 
 func (s *Server) HandleGet(w http.ResponseWriter, r *http.Request) error {
 	slug := chi.URLParam(r, "slug")
 
-	a, err := s.service.Get(slug)
+	currentUser, _ := app.UserFromContext(r.Context())
+
+	a, err := s.service.Get(r.Context(), slug, app.ProfileFromUser(currentUser))
 	if err != nil {
 		return err
 	}
@@ -70,6 +155,7 @@ func (s *Server) HandleGet(w http.ResponseWriter, r *http.Request) error {
 		return app.InternalError(errors.Wrap(err, "json.Marshal"))
 	}
 
+	w.Header().Set("ETag", etag(a))
 	w.Write(resp)
 	return nil
 }
@@ -85,33 +171,24 @@ func (s *Server) HandleFeed(w http.ResponseWriter, r *http.Request) error {
 	}
 
 	filter.CurrentUser = currentUser
+	filter.FeedOf = currentUser
 
-	if limit := params.Get("limit"); limit != "" {
-		l, err := strconv.ParseUint(limit, 10, 64)
-		if err != nil {
-			return app.ServiceError(errorArticleInvalidLimit)
-		}
-		filter.Limit = l
-	}
-
-	if offset := params.Get("offset"); offset != "" {
-		o, err := strconv.ParseUint(offset, 10, 64)
-		if err != nil {
-			return app.ServiceError(errorArticleInvalidOffset)
-		}
-		filter.Offset = o
+	err := parsePagination(params, &filter)
+	if err != nil {
+		return err
 	}
 
 	// Get the article list from service
-	articles, err := s.service.List(&filter)
+	articles, count, err := s.service.List(r.Context(), &filter)
 	if err != nil {
 		return err
 	}
 
 	// Marshal response
 	resp, err := json.Marshal(ResponseMulti{
-		Articles: articles,
-		Count:    len(articles),
+		Articles:   articles,
+		Count:      count,
+		NextCursor: nextCursor(articles, &filter),
 	})
 	if err != nil {
 		return app.InternalError(errors.Wrap(err, "json.Marshal"))
@@ -125,38 +202,48 @@ func (s *Server) HandleList(w http.ResponseWriter, r *http.Request) error {
 	// Construct filter from query params
 	params := r.URL.Query()
 	filter := app.NewArticleListFilter()
+
+	// Current user is optional here and only affects following and favorited
+	// fields in the response
+	currentUser, _ := app.UserFromContext(r.Context())
+	filter.CurrentUser = currentUser
+
 	if author := params.Get("author"); author != "" {
 		filter.Author = &app.Profile{
 			Name: author,
 		}
 	}
 
-	if limit := params.Get("limit"); limit != "" {
-		l, err := strconv.ParseUint(limit, 10, 64)
-		if err != nil {
-			return app.ServiceError(errorArticleInvalidLimit)
-		}
-		filter.Limit = l
+	if tag := params.Get("tag"); tag != "" {
+		filter.Tag = tag
 	}
 
-	if offset := params.Get("offset"); offset != "" {
-		o, err := strconv.ParseUint(offset, 10, 64)
-		if err != nil {
-			return app.ServiceError(errorArticleInvalidOffset)
+	if favorited := params.Get("favorited"); favorited != "" {
+		filter.Favorited = &app.Profile{
+			Name: favorited,
 		}
-		filter.Offset = o
+	}
+
+	if query := params.Get("q"); query != "" {
+		filter.Query = query
+	}
+
+	err := parsePagination(params, &filter)
+	if err != nil {
+		return err
 	}
 
 	// Get the article list from service
-	articles, err := s.service.List(&filter)
+	articles, count, err := s.service.List(r.Context(), &filter)
 	if err != nil {
 		return err
 	}
 
 	// Marshal response
 	resp, err := json.Marshal(ResponseMulti{
-		Articles: articles,
-		Count:    len(articles),
+		Articles:   articles,
+		Count:      count,
+		NextCursor: nextCursor(articles, &filter),
 	})
 	if err != nil {
 		return app.InternalError(errors.Wrap(err, "json.Marshal"))
@@ -184,7 +271,7 @@ func (s *Server) HandleCreate(w http.ResponseWriter, r *http.Request) error {
 		Id:   currentUser.Id,
 		Name: currentUser.Name,
 	}
-	a, err := s.service.Create(&req, &author)
+	a, err := s.service.Create(r.Context(), &req, &author)
 	if err != nil {
 		return err
 	}
@@ -194,6 +281,7 @@ func (s *Server) HandleCreate(w http.ResponseWriter, r *http.Request) error {
 		return app.InternalError(errors.Wrap(err, "json.Marshal"))
 	}
 
+	w.Header().Set("ETag", etag(a))
 	w.Write(resp)
 	return nil
 }
@@ -218,7 +306,12 @@ func (s *Server) HandleUpdate(w http.ResponseWriter, r *http.Request) error {
 		Id:   currentUser.Id,
 		Name: currentUser.Name,
 	}
-	a, err := s.service.Update(slug, &author, &req)
+	version, err := parseETag(r.Header.Get("If-Match"))
+	if err != nil {
+		return app.ValidationError(errorArticleInvalidETag)
+	}
+
+	a, err := s.service.Update(r.Context(), slug, &author, &req, version)
 	if err != nil {
 		return err
 	}
@@ -228,6 +321,7 @@ func (s *Server) HandleUpdate(w http.ResponseWriter, r *http.Request) error {
 		return app.InternalError(errors.Wrap(err, "json.Marshal"))
 	}
 
+	w.Header().Set("ETag", etag(a))
 	w.Write(resp)
 	return nil
 }
@@ -244,7 +338,7 @@ func (s *Server) HandleDelete(w http.ResponseWriter, r *http.Request) error {
 		Id:   currentUser.Id,
 		Name: currentUser.Name,
 	}
-	err := s.service.Delete(slug, &author)
+	err := s.service.Delete(r.Context(), slug, &author)
 	if err != nil {
 		return err
 	}
@@ -252,3 +346,49 @@ func (s *Server) HandleDelete(w http.ResponseWriter, r *http.Request) error {
 	w.Write(nil)
 	return nil
 }
+
+func (s *Server) HandleFavorite(w http.ResponseWriter, r *http.Request) error {
+	currentUser, ok := app.UserFromContext(r.Context())
+	if !ok {
+		return app.AuthError(app.ErrorUserNotInContext)
+	}
+
+	slug := chi.URLParam(r, "slug")
+
+	a, err := s.service.Favorite(r.Context(), slug, app.ProfileFromUser(currentUser))
+	if err != nil {
+		return err
+	}
+
+	resp, err := json.Marshal(ResponseSingle{Article: *a})
+	if err != nil {
+		return app.InternalError(errors.Wrap(err, "json.Marshal"))
+	}
+
+	w.Header().Set("ETag", etag(a))
+	w.Write(resp)
+	return nil
+}
+
+func (s *Server) HandleUnfavorite(w http.ResponseWriter, r *http.Request) error {
+	currentUser, ok := app.UserFromContext(r.Context())
+	if !ok {
+		return app.AuthError(app.ErrorUserNotInContext)
+	}
+
+	slug := chi.URLParam(r, "slug")
+
+	a, err := s.service.Unfavorite(r.Context(), slug, app.ProfileFromUser(currentUser))
+	if err != nil {
+		return err
+	}
+
+	resp, err := json.Marshal(ResponseSingle{Article: *a})
+	if err != nil {
+		return app.InternalError(errors.Wrap(err, "json.Marshal"))
+	}
+
+	w.Header().Set("ETag", etag(a))
+	w.Write(resp)
+	return nil
+}
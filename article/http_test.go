@@ -1,7 +1,9 @@
 package article
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -13,7 +15,7 @@ import (
 	"github.com/dzeban/conduit/mock"
 )
 
-const testSecret = "test"
+var testSigner = jwt.NewHMACSigner([]byte("test-secret-of-at-least-32-bytes"))
 
 // Equals implements custom comparison of articles needed in tests.
 // It compares all of the fields except slug because it's generated with
@@ -76,7 +78,7 @@ func TestCreateHandler(t *testing.T) {
 			`{"article":{"title":"","description":"some","body":"some"}}`,
 			http.StatusUnprocessableEntity,
 			nil,
-			errorValidationTitleIsRequired,
+			errors.New(`"title":["is required"]`), // field error in response body
 		},
 		{
 			"Valid",
@@ -99,14 +101,14 @@ func TestCreateHandler(t *testing.T) {
 		},
 	}
 
-	s, err := NewHTTP(mock.NewArticleStore(), mock.NewProfilesStore(), []byte(testSecret))
+	s, err := NewHTTP(mock.NewArticleStore(), mock.NewProfilesStore(), testSigner)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			token, err := jwt.New(tt.contextUser, []byte(testSecret))
+			token, err := jwt.New(tt.contextUser, testSigner)
 			if err != nil {
 				t.Errorf("failed to make JWT")
 				return
@@ -150,3 +152,67 @@ func TestCreateHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestFeedHandler(t *testing.T) {
+	store := newTestStore(t)
+	s, err := NewHTTP(store, store, testSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reader follows the author of the articles, author follows nobody
+	err = store.FollowProfile(context.Background(), app.ProfileFromUser(store.reader), app.ProfileFromUser(store.author))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		user   *app.User
+		status int
+		count  int
+	}{
+		{"Unauthorized", nil, http.StatusUnauthorized, 0},
+		{"Empty", store.author, http.StatusOK, 0},
+		{"Following", store.reader, http.StatusOK, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/feed", nil)
+			if tt.user != nil {
+				token, err := jwt.New(tt.user, testSigner)
+				if err != nil {
+					t.Fatal(err)
+				}
+				req.Header.Add("Authorization", "Token "+token)
+			}
+
+			s.ServeHTTP(rr, req)
+
+			resp := rr.Result()
+			body, _ := ioutil.ReadAll(resp.Body)
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("incorrect status, expected %v, got %v", tt.status, resp.StatusCode)
+				t.Errorf("resp body: %v", string(body))
+				return
+			}
+
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var r ResponseMulti
+			err := json.Unmarshal(body, &r)
+			if err != nil {
+				t.Fatalf("invalid response body: %v", err)
+			}
+
+			if len(r.Articles) != tt.count {
+				t.Errorf("expected %v articles in feed, got %v", tt.count, len(r.Articles))
+			}
+		})
+	}
+}
@@ -1,31 +1,58 @@
 package article
 
 import (
+	"context"
+
 	"github.com/dzeban/conduit/app"
 	"github.com/pkg/errors"
 )
 
-func (s *Service) List(filter *app.ArticleListFilter) ([]*app.Article, error) {
+// List returns articles matching the filter along with the total number of
+// matching articles regardless of filter limit and offset.
+func (s *Service) List(ctx context.Context, filter *app.ArticleListFilter) ([]*app.Article, int, error) {
 	// Validate filter
 	err := filter.Validate()
 	if err != nil {
-		return nil, app.ServiceError(err)
+		return nil, 0, app.ValidationError(err)
 	}
 
 	// Fill author id in filter
 	if filter.Author != nil {
-		author, err := s.profileStore.GetProfile(filter.Author.Name, app.ProfileFromUser(filter.CurrentUser))
+		author, err := s.profileStore.GetProfile(ctx, filter.Author.Name, app.ProfileFromUser(filter.CurrentUser))
+		if err == app.ErrorProfileNotFound {
+			// Unknown user has no articles
+			return []*app.Article{}, 0, nil
+		}
 		if err != nil {
-			return nil, app.InternalError(errors.Wrap(err, "failed to get author profile"))
+			return nil, 0, app.InternalError(errors.Wrap(err, "failed to get author profile"))
 		}
 
 		filter.Author.Id = author.Id
 	}
 
-	as, err := s.store.ListArticles(filter)
+	// Fill favoriting user id in filter
+	if filter.Favorited != nil {
+		user, err := s.profileStore.GetProfile(ctx, filter.Favorited.Name, app.ProfileFromUser(filter.CurrentUser))
+		if err == app.ErrorProfileNotFound {
+			// Unknown user has no favorites
+			return []*app.Article{}, 0, nil
+		}
+		if err != nil {
+			return nil, 0, app.InternalError(errors.Wrap(err, "failed to get favoriting user profile"))
+		}
+
+		filter.Favorited.Id = user.Id
+	}
+
+	as, err := s.store.ListArticles(ctx, filter)
+	if err != nil {
+		return nil, 0, app.InternalError(errors.Wrap(err, "failed to get list of articles"))
+	}
+
+	count, err := s.store.CountArticles(ctx, filter)
 	if err != nil {
-		return nil, app.InternalError(errors.Wrap(err, "failed to get list of articles"))
+		return nil, 0, app.InternalError(errors.Wrap(err, "failed to count articles"))
 	}
 
-	return as, nil
+	return as, count, nil
 }
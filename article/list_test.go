@@ -0,0 +1,333 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+
+	"github.com/dzeban/conduit/app"
+	"github.com/dzeban/conduit/memory"
+	"github.com/dzeban/conduit/mock"
+)
+
+// testStore is memory store with copies of mock articles by the author. Mock
+// store doesn't filter, paginate or search articles, so list tests use this
+// one.
+type testStore struct {
+	*memory.Store
+
+	author, reader *app.User
+	valid, updated *app.Article
+}
+
+func newTestStore(t *testing.T) *testStore {
+	s := &testStore{Store: memory.NewStore()}
+
+	s.author = s.addUser(t, mock.Author.Name)
+	s.reader = s.addUser(t, mock.UserUpdatedUsername.Name)
+
+	s.valid = s.addArticle(t, mock.ArticleValid, s.author)
+	s.updated = s.addArticle(t, mock.ArticleUpdated, s.author)
+
+	return s
+}
+
+func (s *testStore) addUser(t *testing.T, name string) *app.User {
+	u := &app.User{Name: name, Email: name + "@example.com"}
+	if err := s.AddUser(context.Background(), u); err != nil {
+		t.Fatal(err)
+	}
+
+	return u
+}
+
+// addArticle creates copy of article by the author. Id and version are set
+// by the store.
+func (s *testStore) addArticle(t *testing.T, a app.Article, author *app.User) *app.Article {
+	a.Id = 0
+	a.Version = 0
+	a.Author = *app.ProfileFromUser(author)
+	a.TagList = append([]string(nil), a.TagList...)
+
+	if err := s.CreateArticle(context.Background(), &a); err != nil {
+		t.Fatal(err)
+	}
+
+	return &a
+}
+
+func TestListByTag(t *testing.T) {
+	tests := []struct {
+		name  string
+		tag   string
+		count int
+	}{
+		{"NoTag", "", 2},
+		{"Tagged", "go", 1},
+		{"Absent", "absent", 0},
+	}
+
+	store := newTestStore(t)
+	s := NewService(store, store)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := app.NewArticleListFilter()
+			filter.Tag = tt.tag
+
+			articles, _, err := s.List(context.Background(), &filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(articles) != tt.count {
+				t.Errorf("List(tag=%q): expected %v articles, got %v", tt.tag, tt.count, len(articles))
+			}
+		})
+	}
+}
+
+func TestListUnknownAuthor(t *testing.T) {
+	s := NewService(mock.NewArticleStore(), mock.NewProfilesStore())
+
+	filter := app.NewArticleListFilter()
+	filter.Author = &app.Profile{Name: "absent"}
+	articles, count, err := s.List(context.Background(), &filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(articles) != 0 || count != 0 {
+		t.Errorf("List(author=absent): expected no articles, got %v articles, count %v", len(articles), count)
+	}
+}
+
+func TestListFollowing(t *testing.T) {
+	store := newTestStore(t)
+	s := NewService(store, store)
+
+	// Article by other author that is not followed by anyone
+	other := store.addArticle(t, app.Article{
+		Slug:    "other",
+		Title:   "Other",
+		Body:    "Other",
+		Created: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+	}, store.addUser(t, "other"))
+
+	viewer := store.reader
+	err := store.FollowProfile(context.Background(), app.ProfileFromUser(viewer), app.ProfileFromUser(store.author))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid, updated := store.valid.Id, store.updated.Id
+
+	tests := []struct {
+		name        string
+		currentUser *app.User
+		feedOf      *app.User
+		count       int
+		following   map[int]bool // article id to author following flag
+	}{
+		{
+			"Anonymous",
+			nil,
+			nil,
+			3,
+			map[int]bool{valid: false, updated: false, other.Id: false},
+		},
+		{
+			"Viewer",
+			viewer,
+			nil,
+			3,
+			map[int]bool{valid: true, updated: true, other.Id: false},
+		},
+		{
+			"Feed",
+			viewer,
+			viewer,
+			2,
+			map[int]bool{valid: true, updated: true},
+		},
+		{
+			"EmptyFeed",
+			store.author,
+			store.author,
+			0,
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := app.NewArticleListFilter()
+			filter.CurrentUser = tt.currentUser
+			filter.FeedOf = tt.feedOf
+
+			articles, _, err := s.List(context.Background(), &filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(articles) != tt.count {
+				t.Errorf("expected %v articles, got %v", tt.count, len(articles))
+			}
+
+			for _, a := range articles {
+				following, ok := tt.following[a.Id]
+				if !ok {
+					t.Errorf("unexpected article %v in the list", a.Id)
+					continue
+				}
+
+				if a.Author.Following != following {
+					t.Errorf("article %v: expected following %v, got %v", a.Id, following, a.Author.Following)
+				}
+			}
+		})
+	}
+}
+
+func TestListCount(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  uint64
+		offset uint64
+		len    int
+	}{
+		{"All", 20, 0, 2},
+		{"Limit", 1, 0, 1},
+		{"Offset", 20, 1, 1},
+		{"OffsetTooBig", 20, 5, 0},
+	}
+
+	store := newTestStore(t)
+	s := NewService(store, store)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := app.NewArticleListFilter()
+			filter.Limit = tt.limit
+			filter.Offset = tt.offset
+
+			articles, count, err := s.List(context.Background(), &filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(articles) != tt.len {
+				t.Errorf("expected %v articles, got %v", tt.len, len(articles))
+			}
+
+			// Total count doesn't depend on limit and offset
+			if count != 2 {
+				t.Errorf("expected total count 2, got %v", count)
+			}
+		})
+	}
+}
+
+func TestListCursor(t *testing.T) {
+	store := newTestStore(t)
+	s := NewService(store, store)
+
+	// Article created at the same time as the valid one to check that cursor
+	// handles ties by id
+	tie := store.addArticle(t, app.Article{
+		Slug:    "tie",
+		Title:   "Tie",
+		Body:    "Tie",
+		Created: store.valid.Created,
+	}, store.author)
+
+	expected := []int{tie.Id, store.valid.Id, store.updated.Id}
+
+	var (
+		got    []int
+		cursor *app.ArticleCursor
+	)
+	for page := 0; page < len(expected)+1; page++ {
+		filter := app.NewArticleListFilter()
+		filter.Limit = 1
+		filter.After = cursor
+
+		articles, count, err := s.List(context.Background(), &filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if count != len(expected) {
+			t.Errorf("page %v: expected total count %v, got %v", page, len(expected), count)
+		}
+
+		if len(articles) == 0 {
+			break
+		}
+
+		got = append(got, articles[0].Id)
+
+		// Pass cursor through its string form like clients do
+		cursor, err = app.ParseArticleCursor(app.NewArticleCursor(articles[0]).String())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if diff := deep.Equal(got, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestListSearch(t *testing.T) {
+	store := newTestStore(t)
+	s := NewService(store, store)
+
+	// Article that matches query in body only must go after the one that
+	// matches in title even though it's more recent
+	bodyMatch := store.addArticle(t, app.Article{
+		Slug:    "body-match",
+		Title:   "Something",
+		Body:    "This is the other title mentioned in body",
+		Created: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+	}, store.author)
+
+	filter := app.NewArticleListFilter()
+	filter.Query = "OTHER TITLE"
+
+	articles, count, err := s.List(context.Background(), &filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 2 || len(articles) != 2 {
+		t.Fatalf("expected 2 articles found, got %v (count %v)", len(articles), count)
+	}
+
+	if articles[0].Id != store.updated.Id || articles[1].Id != bodyMatch.Id {
+		t.Errorf("unexpected search results order: %v, %v", articles[0].Id, articles[1].Id)
+	}
+
+	expected := "This is the <b>other</b> <b>title</b> mentioned in body"
+	if articles[1].Snippet != expected {
+		t.Errorf("unexpected snippet, expected %q, got %q", expected, articles[1].Snippet)
+	}
+}
+
+func TestListSearchWithCursor(t *testing.T) {
+	s := NewService(mock.NewArticleStore(), mock.NewProfilesStore())
+
+	filter := app.NewArticleListFilter()
+	filter.Query = "title"
+	filter.After = app.NewArticleCursor(&mock.ArticleValid)
+
+	_, _, err := s.List(context.Background(), &filter)
+
+	var e app.Error
+	if !errors.As(err, &e) || e.Type != app.ErrorTypeValidation {
+		t.Errorf("expected service error, got '%v'", err)
+	}
+}
@@ -1,7 +1,9 @@
 package article
 
 import (
+	"context"
 	"regexp"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -13,27 +15,34 @@ var (
 	errorArticleNotFound        = errors.New("article not found")
 	errorArticleUpdateForbidden = errors.New("article update forbidden")
 	errorArticleDeleteForbidden = errors.New("article delete forbidden")
+	errorArticleVersionConflict = errors.New("article was modified by someone else")
+	errorArticleInvalidETag     = errors.New("invalid If-Match header")
 	errorInvalidRequest         = errors.New("invlaid request")
 	errorArticleInvalidLimit    = errors.New("invalid limit")
 	errorArticleInvalidOffset   = errors.New("invalid offset")
-
-	errorValidationTitleIsRequired = errors.New("title is required")
-	errorValidationBodyIsRequired  = errors.New("body is required")
+	errorArticleInvalidCursor   = errors.New("invalid cursor")
 )
 
 // ArticleStore defines an interface to work with articles
 type Store interface {
-	CreateArticle(a *app.Article) error
-	GetArticle(slug string) (*app.Article, error)
-	ListArticles(f *app.ArticleListFilter) ([]*app.Article, error)
-	UpdateArticle(a *app.Article) error
-	DeleteArticle(id int) error
+	CreateArticle(ctx context.Context, a *app.Article) error
+	GetArticle(ctx context.Context, slug string, viewer *app.Profile) (*app.Article, error)
+	ListArticles(ctx context.Context, f *app.ArticleListFilter) ([]*app.Article, error)
+	CountArticles(ctx context.Context, f *app.ArticleListFilter) (int, error)
+
+	// UpdateArticle modifies the article only if its version in the store
+	// matches a.Version. It returns app.ErrorArticleVersionMismatch otherwise.
+	UpdateArticle(ctx context.Context, a *app.Article) error
+
+	DeleteArticle(ctx context.Context, id int) error
+	FavoriteArticle(ctx context.Context, user *app.Profile, a *app.Article) error
+	UnfavoriteArticle(ctx context.Context, user *app.Profile, a *app.Article) error
 }
 
 // ProfilesStore provides helper to get author with all its fields (like id) by
 // username
 type ProfilesStore interface {
-	GetProfile(username string, follower *app.Profile) (*app.Profile, error)
+	GetProfile(ctx context.Context, username string, follower *app.Profile) (*app.Profile, error)
 }
 
 // Service provides methods for articles
@@ -50,3 +59,21 @@ func NewService(store Store, profileStore ProfilesStore) *Service {
 // empty is regexp to validate for "empty" string.
 // Empty string is the one with zero length or containing only whitespaces.
 var empty = regexp.MustCompile(`^[[:space:]]*$`)
+
+// normalizeTags trims whitespaces around tags and removes empty and duplicate
+// tags preserving the original order.
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
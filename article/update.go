@@ -1,6 +1,7 @@
 package article
 
 import (
+	"context"
 	"time"
 
 	"github.com/pkg/errors"
@@ -16,13 +17,18 @@ type UpdateArticle struct {
 	Title       string `json:"title,omitempty"`
 	Description string `json:"description,omitempty"`
 	Body        string `json:"body,omitempty"`
+
+	// TagList replaces article tags when it's not nil. Empty list removes all
+	// tags from the article.
+	TagList []string `json:"tagList,omitempty"`
 }
 
 func (r *UpdateRequest) Validate() error {
 	if empty.MatchString(r.Article.Title) &&
 		empty.MatchString(r.Article.Description) &&
-		empty.MatchString(r.Article.Body) {
-		return errors.New("at least one of title, description, body is required for update")
+		empty.MatchString(r.Article.Body) &&
+		r.Article.TagList == nil {
+		return errors.New("at least one of title, description, body, tagList is required for update")
 	}
 
 	return nil
@@ -30,26 +36,37 @@ func (r *UpdateRequest) Validate() error {
 
 // Update modifies article found by slug with the new data in req.
 // Returns updated article.
-func (s *Service) Update(slug string, author *app.Profile, req *UpdateRequest) (*app.Article, error) {
+//
+// Update uses optimistic concurrency control. Version is the article version
+// the client has seen, update fails with conflict error if the article was
+// modified since then. Zero version means the client doesn't care about
+// concurrent updates, but the article still won't be modified if it changes
+// between reading and writing it here.
+func (s *Service) Update(ctx context.Context, slug string, author *app.Profile, req *UpdateRequest, version int) (*app.Article, error) {
 	// Validate request
 	err := req.Validate()
 	if err != nil {
-		return nil, app.ServiceError(err)
+		return nil, app.ValidationError(err)
 	}
 
 	// Find article
-	a, err := s.store.GetArticle(slug)
+	a, err := s.store.GetArticle(ctx, slug, author)
 	if err != nil {
 		return nil, app.InternalError(errors.Wrap(err, "failed to get article for update"))
 	}
 
 	if a == nil {
-		return nil, app.ServiceError(errorArticleNotFound)
+		return nil, app.NotFoundError(errorArticleNotFound)
 	}
 
 	// Check that article belongs to author
 	if a.Author.Id != author.Id {
-		return nil, app.ServiceError(errorArticleUpdateForbidden)
+		return nil, app.ForbiddenError(errorArticleUpdateForbidden)
+	}
+
+	// Check that client has seen the current version of the article
+	if version != 0 && version != a.Version {
+		return nil, app.ConflictError(errorArticleVersionConflict)
 	}
 
 	// Fill updated fields
@@ -65,17 +82,24 @@ func (s *Service) Update(slug string, author *app.Profile, req *UpdateRequest) (
 		a.Body = req.Article.Body
 	}
 
+	if req.Article.TagList != nil {
+		a.TagList = normalizeTags(req.Article.TagList)
+	}
+
 	// Refresh updated timestamp
 	a.Updated = time.Now()
 
 	// Persist updated article in the store
-	err = s.store.UpdateArticle(a)
+	err = s.store.UpdateArticle(ctx, a)
+	if err == app.ErrorArticleVersionMismatch {
+		return nil, app.ConflictError(errorArticleVersionConflict)
+	}
 	if err != nil {
 		return nil, app.InternalError(errors.Wrap(err, "failed to update article"))
 	}
 
 	// Return updated article
-	a, err = s.store.GetArticle(slug)
+	a, err = s.store.GetArticle(ctx, slug, author)
 	if err != nil {
 		return nil, app.InternalError(errors.Wrap(err, "failed to get article after update"))
 	}
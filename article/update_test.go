@@ -1,6 +1,7 @@
 package article
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -27,7 +28,7 @@ func TestUpdate(t *testing.T) {
 					Body:        "\n",
 				},
 			},
-			app.ErrorTypeService,
+			app.ErrorTypeValidation,
 			nil,
 			nil,
 		},
@@ -39,7 +40,7 @@ func TestUpdate(t *testing.T) {
 					Title: "new",
 				},
 			},
-			app.ErrorTypeService,
+			app.ErrorTypeNotFound,
 			errorArticleNotFound,
 			nil,
 		},
@@ -88,7 +89,7 @@ func TestUpdate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a, err := s.Update(tt.slug, &mock.Author, &tt.req)
+			a, err := s.Update(context.Background(), tt.slug, &mock.Author, &tt.req, 0)
 			if err != nil {
 				// Check error
 				var e app.Error
@@ -129,11 +130,11 @@ func TestUpdateTimestamp(t *testing.T) {
 	s := NewService(mock.NewArticleStore(), mock.NewProfilesStore())
 
 	prevUpdated := mock.ArticleValid.Updated
-	a, err := s.Update(mock.ArticleValid.Slug, &mock.Author, &UpdateRequest{
+	a, err := s.Update(context.Background(), mock.ArticleValid.Slug, &mock.Author, &UpdateRequest{
 		UpdateArticle{
 			Title: "new title",
 		},
-	})
+	}, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -150,13 +151,45 @@ func TestUpdateAuthorCheck(t *testing.T) {
 		Id:   999,
 		Name: "Evil",
 	}
-	_, err := s.Update(mock.ArticleValid.Slug, &invalidAuthor, &UpdateRequest{
+	_, err := s.Update(context.Background(), mock.ArticleValid.Slug, &invalidAuthor, &UpdateRequest{
 		UpdateArticle{
 			Title: "new title",
 		},
-	})
+	}, 0)
 
 	if !errors.Is(err, errorArticleUpdateForbidden) {
 		t.Errorf("invalid error, expected '%v', got '%v'", errorArticleUpdateForbidden, err)
 	}
 }
+
+func TestUpdateVersion(t *testing.T) {
+	s := NewService(mock.NewArticleStore(), mock.NewProfilesStore())
+
+	req := &UpdateRequest{
+		UpdateArticle{
+			Title: "new title",
+		},
+	}
+
+	a, err := s.Get(context.Background(), mock.ArticleValid.Slug, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := s.Update(context.Background(), a.Slug, &mock.Author, req, a.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updated.Version != a.Version+1 {
+		t.Errorf("version was not incremented: prev %v, current %v", a.Version, updated.Version)
+	}
+
+	// Update with the stale version must fail
+	_, err = s.Update(context.Background(), a.Slug, &mock.Author, req, a.Version)
+
+	var e app.Error
+	if !errors.As(err, &e) || e.Type != app.ErrorTypeConflict {
+		t.Errorf("invalid error for stale version, expected conflict, got '%v'", err)
+	}
+}
@@ -14,6 +14,7 @@ var CreateOpts = []string{
 	"title",
 	"description",
 	"body",
+	"tags",
 }
 
 func Create(c *ishell.Context) {
@@ -33,6 +34,8 @@ func Create(c *ishell.Context) {
 			req.Article.Description = kv[1]
 		case "body":
 			req.Article.Body = kv[1]
+		case "tags":
+			req.Article.TagList = strings.Split(kv[1], ",")
 		}
 	}
 
@@ -0,0 +1,48 @@
+package article
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abiosoft/ishell"
+
+	"github.com/dzeban/conduit/cmd/cli/debug"
+)
+
+var FavoriteOpts = []string{
+	"slug",
+}
+
+func Favorite(c *ishell.Context) {
+	favorite(c, "POST")
+}
+
+var UnfavoriteOpts = []string{
+	"slug",
+}
+
+func Unfavorite(c *ishell.Context) {
+	favorite(c, "DELETE")
+}
+
+func favorite(c *ishell.Context, method string) {
+	if len(c.Args) < 1 {
+		fmt.Println("slug is required")
+		return
+	}
+
+	kv := strings.Split(c.Args[0], "=")
+	if len(kv) != 2 {
+		fmt.Println("invalid option format, need k=v")
+		return
+	}
+
+	slug := kv[1]
+
+	url := fmt.Sprintf("http://localhost:8080/articles/%s/favorite", slug)
+	_, _, err := debug.MakeAuthorizedRequestWithDump(method, url, nil)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+}
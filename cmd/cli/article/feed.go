@@ -11,6 +11,7 @@ import (
 var FeedOpts = []string{
 	"limit",
 	"offset",
+	"cursor",
 }
 
 func Feed(c *ishell.Context) {
@@ -11,7 +11,11 @@ import (
 var ListOpts = []string{
 	"limit",
 	"offset",
+	"cursor",
 	"author",
+	"tag",
+	"favorited",
+	"q",
 }
 
 func List(c *ishell.Context) {
@@ -15,6 +15,7 @@ var UpdateOpts = []string{
 	"title",
 	"description",
 	"body",
+	"tags",
 }
 
 func Update(c *ishell.Context) {
@@ -37,6 +38,8 @@ func Update(c *ishell.Context) {
 			req.Article.Description = kv[1]
 		case "body":
 			req.Article.Body = kv[1]
+		case "tags":
+			req.Article.TagList = strings.Split(kv[1], ",")
 		}
 	}
 
@@ -134,6 +134,20 @@ func main() {
 			CompleterWithPrefix: OptsCompleter(article.DeleteOpts),
 		})
 
+		articleCmd.AddCmd(&ishell.Cmd{
+			Name:                "favorite",
+			Help:                "Favorite article",
+			Func:                article.Favorite,
+			CompleterWithPrefix: OptsCompleter(article.FavoriteOpts),
+		})
+
+		articleCmd.AddCmd(&ishell.Cmd{
+			Name:                "unfavorite",
+			Help:                "Unfavorite article",
+			Func:                article.Unfavorite,
+			CompleterWithPrefix: OptsCompleter(article.UnfavoriteOpts),
+		})
+
 		cli.AddCmd(articleCmd)
 	}
 
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const migrateUsage = "usage: conduit migrate up|down [n]|status [flags]"
+
+// parseCommand splits command line into the migrate subcommand and config
+// flags. Subcommand is nil when the server should be started.
+func parseCommand(args []string) (command []string, flags []string, err error) {
+	if len(args) == 0 || args[0] != "migrate" {
+		return nil, args, nil
+	}
+
+	if len(args) < 2 {
+		return nil, nil, errors.New(migrateUsage)
+	}
+
+	switch args[1] {
+	case "up", "status":
+		return args[:2], args[2:], nil
+
+	case "down":
+		if len(args) > 2 && !strings.HasPrefix(args[2], "-") {
+			n, err := strconv.Atoi(args[2])
+			if err != nil || n < 1 {
+				return nil, nil, errors.Errorf("invalid number of migrations to revert %q", args[2])
+			}
+
+			return args[:3], args[3:], nil
+		}
+
+		return args[:2], args[2:], nil
+
+	default:
+		return nil, nil, errors.New(migrateUsage)
+	}
+}
+
+// migrate runs migrate subcommand checked by parseCommand and prints the
+// resulting status
+func migrate(ctx context.Context, store Migrator, command []string) error {
+	switch command[1] {
+	case "up":
+		err := store.Migrate(ctx)
+		if err != nil {
+			return err
+		}
+
+	case "down":
+		n := 1
+		if len(command) == 3 {
+			n, _ = strconv.Atoi(command[2])
+		}
+
+		err := store.MigrateDown(ctx, n)
+		if err != nil {
+			return err
+		}
+	}
+
+	return printMigrationStatus(ctx, store)
+}
+
+func printMigrationStatus(ctx context.Context, store Migrator) error {
+	statuses, err := store.MigrationStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+	for _, s := range statuses {
+		applied := "pending"
+		if s.Applied != nil {
+			applied = s.Applied.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, applied)
+	}
+
+	return w.Flush()
+}
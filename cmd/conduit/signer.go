@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/jwt"
+)
+
+// newSigner creates JWT signer from config. Secret is used as HS256 key when
+// key file is not configured, it's an error when both are empty.
+func newSigner(config JWTConfig) (*jwt.Signer, error) {
+	var current *jwt.Key
+	if config.KeyFile == "" {
+		if config.Alg != jwt.AlgHS256 {
+			return nil, fmt.Errorf("key file is required for %s", config.Alg)
+		}
+
+		if config.Secret == "" {
+			return nil, errors.New("JWT secret or key file is required")
+		}
+
+		key, err := jwt.NewHMACKey(config.KeyId, []byte(config.Secret))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid JWT secret")
+		}
+
+		current = key
+	} else {
+		key, err := jwt.LoadKey(config.KeyId, config.Alg, config.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load signing key")
+		}
+
+		current = key
+	}
+
+	var others []*jwt.Key
+	for _, verifyKey := range config.VerifyKeys {
+		parts := strings.SplitN(verifyKey, ":", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid verification key %q, expected kid:alg:path", verifyKey)
+		}
+
+		key, err := jwt.LoadKey(parts[0], parts[1], parts[2])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load verification key %q", parts[0])
+		}
+
+		others = append(others, key)
+	}
+
+	return jwt.NewSigner(current, others...)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -8,13 +9,57 @@ import (
 	"github.com/dzeban/conduit/password"
 )
 
-func generate(input string) {
-	s, err := password.HashAndEncode(input)
+const usage = `Usage:
+  %[1]s [flags] <string>                  hash string
+  %[1]s [flags] <string> <encoded hash>   compare string to hash
+  %[1]s decode <encoded hash>             print hash params
+  %[1]s tune [flags]                      recommend hash params for this host
+
+Run "%[1]s tune -h" for tune flags.
+
+Flags:
+`
+
+// paramsFlags holds hash params set from command line
+type paramsFlags struct {
+	iterations uint
+	memory     uint
+	threads    uint
+	len        uint
+}
+
+// register adds hash params flags to fs with package defaults
+func (f *paramsFlags) register(fs *flag.FlagSet) {
+	defaults := password.Defaults()
+
+	fs.UintVar(&f.iterations, "iterations", uint(defaults.Iterations), "number of passes over the memory")
+	fs.UintVar(&f.memory, "memory", uint(defaults.Memory), "memory size in KiB")
+	fs.UintVar(&f.threads, "threads", uint(defaults.Threads), "number of threads")
+	fs.UintVar(&f.len, "len", uint(defaults.Len), "hash length in bytes")
+}
+
+// params returns hash params with new random salt
+func (f *paramsFlags) params() (password.HashParams, error) {
+	if f.iterations == 0 || f.memory == 0 || f.threads == 0 || f.threads > 255 || f.len == 0 {
+		return password.HashParams{}, password.ErrHashParams
+	}
+
+	params, err := password.NewHashParams()
 	if err != nil {
-		log.Fatal(err)
+		return password.HashParams{}, err
 	}
 
-	fmt.Println(s)
+	params.Iterations = uint32(f.iterations)
+	params.Memory = uint32(f.memory)
+	params.Threads = uint8(f.threads)
+	params.Len = uint32(f.len)
+
+	return params, nil
+}
+
+func generate(input string, params password.HashParams) {
+	hash := password.HashWithParams(input, params)
+	fmt.Println(password.Encode(hash, params))
 }
 
 func compare(input, encodedHash string) {
@@ -26,17 +71,69 @@ func compare(input, encodedHash string) {
 	fmt.Println(equals)
 }
 
+func decode(encodedHash string) {
+	hash, params, err := password.Decode(encodedHash)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("iterations: %d\n", params.Iterations)
+	fmt.Printf("memory:     %d KiB\n", params.Memory)
+	fmt.Printf("threads:    %d\n", params.Threads)
+	fmt.Printf("len:        %d\n", params.Len)
+	fmt.Printf("salt len:   %d\n", len(params.Salt))
+	fmt.Printf("hash len:   %d\n", len(hash))
+
+	if params.Weaker(password.Defaults()) {
+		fmt.Println("weaker than defaults, will be rehashed on login")
+	}
+}
+
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Printf("Usage: %s <string> [<encoded hash>]\n", os.Args[0])
-		os.Exit(1)
+	log.SetFlags(0)
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "decode":
+			if len(os.Args) != 3 {
+				fmt.Fprintf(os.Stderr, "Usage: %s decode <encoded hash>\n", os.Args[0])
+				os.Exit(1)
+			}
+
+			decode(os.Args[2])
+			return
+
+		case "tune":
+			tune(os.Args[0], os.Args[2:])
+			return
+		}
 	}
 
-	if len(os.Args) == 2 {
-		generate(os.Args[1])
+	var pf paramsFlags
+
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), usage, os.Args[0])
+		fs.PrintDefaults()
 	}
+	pf.register(fs)
+
+	_ = fs.Parse(os.Args[1:])
 
-	if len(os.Args) == 3 {
-		compare(os.Args[1], os.Args[2])
+	switch fs.NArg() {
+	case 1:
+		params, err := pf.params()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		generate(fs.Arg(0), params)
+
+	case 2:
+		compare(fs.Arg(0), fs.Arg(1))
+
+	default:
+		fs.Usage()
+		os.Exit(1)
 	}
 }
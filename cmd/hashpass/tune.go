@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/dzeban/conduit/password"
+)
+
+// measure returns the fastest of rounds hashing durations with params. It's
+// at least 1ns even when a coarse clock measures zero, so it's safe to divide
+// by.
+func measure(params password.HashParams, rounds int) time.Duration {
+	var fastest time.Duration
+	for i := 0; i < rounds; i++ {
+		start := time.Now()
+		password.HashWithParams("benchmark password", params)
+		elapsed := time.Since(start)
+
+		if i == 0 || elapsed < fastest {
+			fastest = elapsed
+		}
+	}
+
+	if fastest < time.Nanosecond {
+		fastest = time.Nanosecond
+	}
+
+	fmt.Printf("iterations=%d memory=%d threads=%d: %v\n",
+		params.Iterations, params.Memory, params.Threads, fastest.Round(time.Millisecond))
+
+	return fastest
+}
+
+// tune finds hash params that take about the target time on this host. Like
+// RFC 9106 suggests, it picks the largest memory that fits the target with a
+// single pass and then adds passes while they fit too.
+func tune(name string, args []string) {
+	threadsDefault := runtime.NumCPU()
+	if threadsDefault > 255 {
+		threadsDefault = 255
+	}
+
+	fs := flag.NewFlagSet(name+" tune", flag.ExitOnError)
+	target := fs.Duration("target", 500*time.Millisecond, "target hashing time")
+	maxMemory := fs.Uint("max-memory", 256*1024, "maximum memory size in KiB")
+	minMemory := fs.Uint("min-memory", 8*1024, "minimum memory size in KiB")
+	threads := fs.Uint("threads", uint(threadsDefault), "number of threads. Note that concurrent logins share the CPUs.")
+	rounds := fs.Int("rounds", 3, "number of measurements for each params, the fastest one is used")
+	_ = fs.Parse(args)
+
+	if *target <= 0 || *minMemory == 0 || *maxMemory < *minMemory ||
+		*threads == 0 || *threads > 255 || *rounds < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	params, err := password.NewHashParams()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	params.Iterations = 1
+	params.Memory = uint32(*maxMemory)
+	params.Threads = uint8(*threads)
+
+	// Halve memory until a single pass fits the target
+	elapsed := measure(params, *rounds)
+	for elapsed > *target && params.Memory/2 >= uint32(*minMemory) {
+		params.Memory /= 2
+		elapsed = measure(params, *rounds)
+	}
+
+	// Estimate the number of passes assuming linear cost and step back until
+	// it fits the target
+	single := elapsed
+	if elapsed < *target {
+		params.Iterations = uint32(*target / elapsed)
+		for params.Iterations > 1 {
+			elapsed = measure(params, *rounds)
+			if elapsed <= *target {
+				break
+			}
+
+			params.Iterations--
+		}
+
+		if params.Iterations == 1 {
+			elapsed = single
+		}
+	}
+
+	if elapsed > *target {
+		fmt.Printf("\nminimum memory doesn't fit the target, hashing takes %v\n", elapsed.Round(time.Millisecond))
+	}
+
+	fmt.Printf("\nrecommended params, hashing takes %v:\n", elapsed.Round(time.Millisecond))
+	fmt.Printf("  -password-iterations=%d -password-memory=%d -password-threads=%d\n",
+		params.Iterations, params.Memory, params.Threads)
+}
@@ -0,0 +1,61 @@
+package comment
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+)
+
+type CreateRequest struct {
+	Comment CommentRequest `json:"comment"`
+}
+
+type CommentRequest struct {
+	Body string `json:"body"`
+}
+
+// Validate checks the request and returns app.FieldErrors with all of the
+// problems found
+func (r *CreateRequest) Validate() error {
+	errs := app.FieldErrors{}
+
+	if empty.MatchString(r.Comment.Body) {
+		errs.Add("body", "is required")
+	}
+
+	return errs.Err()
+}
+
+// Create adds new comment from author to the article found by slug
+func (s *Service) Create(ctx context.Context, slug string, req *CreateRequest, author *app.Profile) (*app.Comment, error) {
+	// Validate request
+	err := req.Validate()
+	if err != nil {
+		return nil, app.ValidationError(err)
+	}
+
+	a, err := s.getArticle(ctx, slug, author)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	comment := &app.Comment{
+		ArticleId: a.Id,
+		Body:      req.Comment.Body,
+		Author:    *author,
+		Created:   now,
+		Updated:   now,
+	}
+
+	// Persist comment in the store
+	err = s.store.CreateComment(ctx, comment)
+	if err != nil {
+		return nil, app.InternalError(errors.Wrap(err, "failed to create comment"))
+	}
+
+	return comment, nil
+}
@@ -0,0 +1,81 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dzeban/conduit/app"
+	"github.com/dzeban/conduit/mock"
+)
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		slug    string
+		req     *CreateRequest
+		errType app.ErrorType
+		err     error
+	}{
+		{
+			"EmptyValidation",
+			mock.ArticleValid.Slug,
+			&CreateRequest{CommentRequest{Body: " \n"}},
+			app.ErrorTypeValidation,
+			app.FieldErrors{"body": {"is required"}},
+		},
+		{
+			"NonExistingArticle",
+			"absent",
+			&CreateRequest{CommentRequest{Body: "new"}},
+			app.ErrorTypeNotFound,
+			errorArticleNotFound,
+		},
+		{
+			"Valid",
+			mock.ArticleValid.Slug,
+			&CreateRequest{CommentRequest{Body: "new"}},
+			0,
+			nil,
+		},
+	}
+
+	s := NewService(mock.NewCommentStore(), mock.NewArticleStore())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := s.Create(context.Background(), tt.slug, tt.req, &mock.Profile2)
+			if err != nil {
+				var e app.Error
+				if !errors.As(err, &e) {
+					t.Errorf("Create(%v): invalid error: expected %T, got %T", tt.req, e, err)
+					return
+				}
+
+				if e.Type != tt.errType {
+					t.Errorf("Create(%v): invalid error type: expected %v, got %v", tt.req, tt.errType, e.Type)
+					return
+				}
+
+				if tt.err != nil && !reflect.DeepEqual(e.Err, tt.err) {
+					t.Errorf("Create(%v): invalid error value: expected %v, got %v", tt.req, tt.err, e.Err)
+				}
+				return
+			}
+
+			if c.Body != tt.req.Comment.Body || c.Author.Id != mock.Profile2.Id {
+				t.Errorf("Create(%v): unexpected comment %+v", tt.req, c)
+			}
+
+			comments, err := s.List(context.Background(), tt.slug, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(comments) != 2 || comments[1].Id != c.Id {
+				t.Errorf("List(%v): expected created comment to be the last one, got %+v", tt.slug, comments)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package comment
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+)
+
+// Delete removes comment by id from the article found by slug. Only comment
+// author is allowed to delete it.
+func (s *Service) Delete(ctx context.Context, slug string, id int, author *app.Profile) error {
+	a, err := s.getArticle(ctx, slug, author)
+	if err != nil {
+		return err
+	}
+
+	c, err := s.store.GetComment(ctx, id)
+	if err != nil {
+		return app.InternalError(errors.Wrap(err, "failed to get comment for delete"))
+	}
+
+	// Comment from other article is the same as absent one
+	if c == nil || c.ArticleId != a.Id {
+		return app.NotFoundError(errorCommentNotFound)
+	}
+
+	// Check that comment belongs to author
+	if c.Author.Id != author.Id {
+		return app.ForbiddenError(errorCommentDeleteForbidden)
+	}
+
+	err = s.store.DeleteComment(ctx, id)
+	if err != nil {
+		return app.InternalError(errors.Wrap(err, "comment delete failed"))
+	}
+
+	return nil
+}
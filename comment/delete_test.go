@@ -0,0 +1,73 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dzeban/conduit/app"
+	"github.com/dzeban/conduit/mock"
+)
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name   string
+		slug   string
+		id     int
+		author *app.Profile
+		err    error
+	}{
+		{
+			"NonExistingArticle",
+			"absent",
+			mock.CommentValid.Id,
+			&mock.Author,
+			errorArticleNotFound,
+		},
+		{
+			"NonExistingComment",
+			mock.ArticleValid.Slug,
+			999,
+			&mock.Author,
+			errorCommentNotFound,
+		},
+		{
+			"OtherArticle",
+			mock.ArticleUpdated.Slug,
+			mock.CommentValid.Id,
+			&mock.Author,
+			errorCommentNotFound,
+		},
+		{
+			"Forbidden",
+			mock.ArticleValid.Slug,
+			mock.CommentValid.Id,
+			&app.Profile{
+				Id:   999,
+				Name: "Evil",
+			},
+			errorCommentDeleteForbidden,
+		},
+		{
+			"Valid",
+			mock.ArticleValid.Slug,
+			mock.CommentValid.Id,
+			&mock.Author,
+			nil,
+		},
+	}
+
+	s := NewService(mock.NewCommentStore(), mock.NewArticleStore())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Delete(context.Background(), tt.slug, tt.id, tt.author)
+			if tt.err == nil && err != nil {
+				t.Errorf("Delete(%v, %v, %v): unexpected error '%v'", tt.slug, tt.id, tt.author, err)
+			}
+
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("Delete(%v, %v, %v): invalid error: expected '%v', got '%v'", tt.slug, tt.id, tt.author, tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package comment
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+	"github.com/dzeban/conduit/jwt"
+	"github.com/dzeban/conduit/transport"
+)
+
+// Server serves comments endpoints. It expects to be mounted under the route
+// with "slug" URL parameter, like "/articles/{slug}/comments".
+type Server struct {
+	router  *chi.Mux
+	service *Service
+	signer  *jwt.Signer
+}
+
+func NewHTTP(store Store, articlesStore ArticlesStore, signer *jwt.Signer, authOpts ...jwt.AuthOption) (*Server, error) {
+	s := &Server{
+		router:  chi.NewRouter(),
+		service: NewService(store, articlesStore),
+		signer:  signer,
+	}
+
+	s.router.
+		With(jwt.Auth(s.signer, jwt.AuthTypeOptional, authOpts...)).
+		Get("/", transport.WithError(s.HandleList))
+
+	// Endpoints protected by JWT auth
+	s.router.Group(func(r chi.Router) {
+		r.Use(jwt.Auth(s.signer, jwt.AuthTypeRequired, authOpts...))
+
+		r.Post("/", transport.WithError(s.HandleCreate))
+		r.Delete("/{id}", transport.WithError(s.HandleDelete))
+	})
+
+	return s, nil
+}
+
+// ServeHTTP implements http.handler interface and uses router ServeHTTP method
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
+
+type ResponseSingle struct {
+	Comment app.Comment `json:"comment"`
+}
+
+type ResponseMulti struct {
+	Comments []*app.Comment `json:"comments"`
+}
+
+func (s *Server) HandleList(w http.ResponseWriter, r *http.Request) error {
+	slug := chi.URLParam(r, "slug")
+
+	currentUser, _ := app.UserFromContext(r.Context())
+
+	comments, err := s.service.List(r.Context(), slug, app.ProfileFromUser(currentUser))
+	if err != nil {
+		return err
+	}
+
+	resp, err := json.Marshal(ResponseMulti{Comments: comments})
+	if err != nil {
+		return app.InternalError(errors.Wrap(err, "json.Marshal"))
+	}
+
+	w.Write(resp)
+	return nil
+}
+
+func (s *Server) HandleCreate(w http.ResponseWriter, r *http.Request) error {
+	currentUser, ok := app.UserFromContext(r.Context())
+	if !ok {
+		return app.AuthError(app.ErrorUserNotInContext)
+	}
+
+	slug := chi.URLParam(r, "slug")
+
+	// Decode comment request from JSON body
+	decoder := json.NewDecoder(r.Body)
+	var req CreateRequest
+	err := decoder.Decode(&req)
+	if err != nil {
+		return app.ServiceError(errorInvalidRequest)
+	}
+
+	c, err := s.service.Create(r.Context(), slug, &req, app.ProfileFromUser(currentUser))
+	if err != nil {
+		return err
+	}
+
+	resp, err := json.Marshal(ResponseSingle{Comment: *c})
+	if err != nil {
+		return app.InternalError(errors.Wrap(err, "json.Marshal"))
+	}
+
+	w.Write(resp)
+	return nil
+}
+
+func (s *Server) HandleDelete(w http.ResponseWriter, r *http.Request) error {
+	currentUser, ok := app.UserFromContext(r.Context())
+	if !ok {
+		return app.AuthError(app.ErrorUserNotInContext)
+	}
+
+	slug := chi.URLParam(r, "slug")
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		return app.ValidationError(errorInvalidCommentId)
+	}
+
+	err = s.service.Delete(r.Context(), slug, id, app.ProfileFromUser(currentUser))
+	if err != nil {
+		return err
+	}
+
+	w.Write(nil)
+	return nil
+}
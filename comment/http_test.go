@@ -0,0 +1,84 @@
+package comment
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+
+	"github.com/dzeban/conduit/jwt"
+	"github.com/dzeban/conduit/mock"
+)
+
+var testSigner = jwt.NewHMACSigner([]byte("test-secret-of-at-least-32-bytes"))
+
+func TestHandlers(t *testing.T) {
+	s, err := NewHTTP(mock.NewCommentStore(), mock.NewArticleStore(), testSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Mount the server like it's done in the app to get slug URL param
+	router := chi.NewRouter()
+	router.Mount("/articles/{slug}/comments", s)
+
+	token, err := jwt.New(&mock.UserValid, testSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	url := "/articles/" + mock.ArticleValid.Slug + "/comments"
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		token  string
+		status int
+	}{
+		{"Unauthorized", http.MethodPost, url, `{"comment":{"body":"new"}}`, "", http.StatusUnauthorized},
+		{"Create", http.MethodPost, url, `{"comment":{"body":"new"}}`, token, http.StatusOK},
+		{"InvalidId", http.MethodDelete, url + "/xxx", "", token, http.StatusUnprocessableEntity},
+		{"NotFound", http.MethodDelete, url + "/999", "", token, http.StatusNotFound},
+		{"AbsentArticle", http.MethodGet, "/articles/absent/comments", "", "", http.StatusNotFound},
+		{"Delete", http.MethodDelete, url + "/1", "", token, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.token != "" {
+				req.Header.Add("Authorization", "Token "+tt.token)
+			}
+
+			router.ServeHTTP(rr, req)
+
+			resp := rr.Result()
+			if resp.StatusCode != tt.status {
+				body, _ := ioutil.ReadAll(resp.Body)
+				t.Errorf("incorrect status, expected %v, got %v", tt.status, resp.StatusCode)
+				t.Errorf("resp body: %v", string(body))
+			}
+		})
+	}
+
+	// Only the comment created above must be left
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, nil))
+
+	var r ResponseMulti
+	err = json.NewDecoder(rr.Result().Body).Decode(&r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.Comments) != 1 || r.Comments[0].Body != "new" {
+		t.Errorf("unexpected comments list %+v", r.Comments)
+	}
+}
@@ -0,0 +1,25 @@
+package comment
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+)
+
+// List returns comments of the article found by slug. Viewer is optional and
+// used to fill following field of comment authors.
+func (s *Service) List(ctx context.Context, slug string, viewer *app.Profile) ([]*app.Comment, error) {
+	a, err := s.getArticle(ctx, slug, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	cs, err := s.store.ListComments(ctx, a.Id, viewer)
+	if err != nil {
+		return nil, app.InternalError(errors.Wrap(err, "failed to get list of comments"))
+	}
+
+	return cs, nil
+}
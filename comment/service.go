@@ -0,0 +1,61 @@
+package comment
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+)
+
+var (
+	errorArticleNotFound        = errors.New("article not found")
+	errorCommentNotFound        = errors.New("comment not found")
+	errorCommentDeleteForbidden = errors.New("comment delete forbidden")
+	errorInvalidRequest         = errors.New("invalid request")
+	errorInvalidCommentId       = errors.New("invalid comment id")
+)
+
+// Store defines an interface to work with comments
+type Store interface {
+	CreateComment(ctx context.Context, c *app.Comment) error
+	GetComment(ctx context.Context, id int) (*app.Comment, error)
+	ListComments(ctx context.Context, articleId int, viewer *app.Profile) ([]*app.Comment, error)
+	DeleteComment(ctx context.Context, id int) error
+}
+
+// ArticlesStore provides helper to get article with all its fields (like id)
+// by slug
+type ArticlesStore interface {
+	GetArticle(ctx context.Context, slug string, viewer *app.Profile) (*app.Article, error)
+}
+
+// Service provides methods for comments
+type Service struct {
+	store        Store
+	articleStore ArticlesStore
+}
+
+// NewService creates new instance of the service with provided stores
+func NewService(store Store, articleStore ArticlesStore) *Service {
+	return &Service{store, articleStore}
+}
+
+// empty is regexp to validate for "empty" string.
+// Empty string is the one with zero length or containing only whitespaces.
+var empty = regexp.MustCompile(`^[[:space:]]*$`)
+
+// getArticle returns article by slug or service error if it's not found
+func (s *Service) getArticle(ctx context.Context, slug string, viewer *app.Profile) (*app.Article, error) {
+	a, err := s.articleStore.GetArticle(ctx, slug, viewer)
+	if err != nil {
+		return nil, app.InternalError(errors.Wrap(err, "failed to get article"))
+	}
+
+	if a == nil {
+		return nil, app.NotFoundError(errorArticleNotFound)
+	}
+
+	return a, nil
+}
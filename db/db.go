@@ -1,8 +1,9 @@
 package db
 
 import (
-	"fmt"
+	"context"
 	"log"
+	"math/rand"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -10,23 +11,105 @@ import (
 	"github.com/pkg/errors"
 )
 
-// ConnectLoop tries to connect to the DB under given DSN using a give driver
-// in a loop until connection succeeds. timeout specifies the timeout for the
-// loop.
-func ConnectLoop(driver, DSN string, timeout time.Duration) (*sqlx.DB, error) {
-	ticker := time.NewTicker(1 * time.Second)
-	timeoutExceeded := time.After(timeout)
+// Default connection retry params used for zero Config fields
+const (
+	DefaultConnectTimeout = 1 * time.Minute
+	DefaultRetryMin       = 500 * time.Millisecond
+	DefaultRetryMax       = 10 * time.Second
+)
+
+// Config describes connection pool limits and how connection is retried on
+// start. Zero pool params keep database/sql defaults, zero retry params are
+// replaced with defaults.
+type Config struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	// ConnectTimeout limits the time spent on connection retries
+	ConnectTimeout time.Duration
+
+	// Delay between connection retries starts from RetryMin and doubles up to
+	// RetryMax
+	RetryMin time.Duration
+	RetryMax time.Duration
+}
+
+// withDefaults returns config with zero retry params replaced by defaults
+func (c Config) withDefaults() Config {
+	if c.ConnectTimeout == 0 {
+		c.ConnectTimeout = DefaultConnectTimeout
+	}
+	if c.RetryMin == 0 {
+		c.RetryMin = DefaultRetryMin
+	}
+	if c.RetryMax == 0 {
+		c.RetryMax = DefaultRetryMax
+	}
+	if c.RetryMax < c.RetryMin {
+		c.RetryMax = c.RetryMin
+	}
+
+	return c
+}
+
+// ConnectLoop tries to connect to the DB under given DSN using a given driver
+// in a loop until connection succeeds or config.ConnectTimeout is exceeded.
+// Delay between attempts grows exponentially with random jitter, so instances
+// started at once don't hammer the DB in lockstep. Pool of the returned DB is
+// configured with the config limits.
+func ConnectLoop(driver, DSN string, config Config) (*sqlx.DB, error) {
+	config = config.withDefaults()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.ConnectTimeout)
+	defer cancel()
+
+	delay := config.RetryMin
 	for {
+		db, err := sqlx.ConnectContext(ctx, driver, DSN)
+		if err == nil {
+			configurePool(db, config)
+			return db, nil
+		}
+		log.Println(errors.Wrapf(err, "failed to connect to %s db", driver))
+
 		select {
-		case <-timeoutExceeded:
-			return nil, fmt.Errorf("db connection failed after %s timeout", timeout)
-
-		case <-ticker.C:
-			db, err := sqlx.Connect("postgres", DSN)
-			if err == nil {
-				return db, nil
-			}
-			log.Println(errors.Wrapf(err, "failed to connect to db %s", DSN))
+		case <-ctx.Done():
+			return nil, errors.Errorf("db connection failed after %s timeout", config.ConnectTimeout)
+
+		case <-time.After(jitter(delay)):
+		}
+
+		delay *= 2
+		if delay > config.RetryMax {
+			delay = config.RetryMax
 		}
 	}
 }
+
+// configurePool applies non-zero pool params of the config
+func configurePool(db *sqlx.DB, config Config) {
+	if config.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(config.MaxOpenConns)
+	}
+	if config.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(config.MaxIdleConns)
+	}
+	if config.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	}
+	if config.ConnMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+	}
+}
+
+// jitter returns random duration between d/2 and d
+func jitter(d time.Duration) time.Duration {
+	half := int64(d / 2)
+	if half <= 0 {
+		return d
+	}
+
+	return time.Duration(half + rand.Int63n(half+1))
+}
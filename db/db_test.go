@@ -0,0 +1,47 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestConnectLoop(t *testing.T) {
+	db, err := ConnectLoop("sqlite3", ":memory:", Config{MaxOpenConns: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if n := db.Stats().MaxOpenConnections; n != 3 {
+		t.Errorf("invalid max open connections, expected 3, got %d", n)
+	}
+}
+
+func TestConnectLoopTimeout(t *testing.T) {
+	config := Config{
+		ConnectTimeout: 100 * time.Millisecond,
+		RetryMin:       10 * time.Millisecond,
+		RetryMax:       20 * time.Millisecond,
+	}
+
+	start := time.Now()
+	_, err := ConnectLoop("unknown", "", config)
+	if err == nil {
+		t.Fatal("connected with unknown driver")
+	}
+
+	if elapsed := time.Since(start); elapsed < config.ConnectTimeout || elapsed > 10*config.ConnectTimeout {
+		t.Errorf("connection is not retried until timeout, elapsed %s", elapsed)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	d := 100 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		if j := jitter(d); j < d/2 || j > d {
+			t.Fatalf("jitter %s is out of [%s, %s]", j, d/2, d)
+		}
+	}
+}
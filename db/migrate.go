@@ -0,0 +1,102 @@
+package db
+
+import (
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// migrationFile matches migration file names like 1_tables.up.sql
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a schema change embedded in the binary
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus describes embedded migration and when it was applied. Applied
+// is nil for pending migrations.
+type MigrationStatus struct {
+	Version int
+	Name    string
+	Applied *time.Time
+}
+
+// LoadMigrations reads migrations from the dir of fsys sorted by version.
+// Files are named like 1_tables.up.sql and 1_tables.down.sql, down file is
+// optional.
+func LoadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	files, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read migrations")
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, f := range files {
+		m := migrationFile.FindStringSubmatch(f.Name())
+		if m == nil {
+			return nil, errors.Errorf("invalid migration file name %s", f.Name())
+		}
+
+		version, _ := strconv.Atoi(m[1])
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != m[2] {
+			return nil, errors.Errorf("migration %d has different names %s and %s", version, migration.Name, m[2])
+		}
+
+		query, err := fs.ReadFile(fsys, path.Join(dir, f.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read migration %s", f.Name())
+		}
+
+		if m[3] == "up" {
+			migration.Up = string(query)
+		} else {
+			migration.Down = string(query)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, errors.Errorf("migration %d has no up file", m.Version)
+		}
+
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Statuses returns status of every migration given the time applied ones were
+// applied at
+func Statuses(migrations []Migration, applied map[int]time.Time) []MigrationStatus {
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if t, ok := applied[m.Version]; ok {
+			status.Applied = &t
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
@@ -1,6 +1,6 @@
 module github.com/dzeban/conduit
 
-go 1.15
+go 1.16
 
 require (
 	github.com/BurntSushi/toml v0.4.1 // indirect
@@ -22,6 +22,7 @@ require (
 	github.com/koding/multiconfig v0.0.0-20171124222453-69c27309b2d7
 	github.com/kylelemons/godebug v1.1.0 // indirect
 	github.com/lib/pq v1.10.3
+	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/pkg/errors v0.9.1
 	github.com/sahilm/fuzzy v0.1.0
 	github.com/tidwall/pretty v1.2.0
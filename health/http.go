@@ -0,0 +1,124 @@
+// Package health reports whether the app can serve requests along with the
+// connection pool statistics of its database. It's meant for load balancer
+// health checks and monitoring.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+	"github.com/dzeban/conduit/transport"
+)
+
+// pingTimeout limits the database check so health check doesn't hang when
+// the pool is exhausted
+const pingTimeout = 2 * time.Second
+
+// DB is implemented by the stores backed by a database
+type DB interface {
+	Ping(ctx context.Context) error
+	Stats() sql.DBStats
+}
+
+type Server struct {
+	router *chi.Mux
+	db     DB
+}
+
+// NewHTTP creates health server. DB is optional, only the server itself is
+// checked when it's nil.
+func NewHTTP(db DB) (*Server, error) {
+	s := &Server{
+		router: chi.NewRouter(),
+		db:     db,
+	}
+
+	s.router.Get("/", transport.WithError(s.HandleHealth))
+
+	return s, nil
+}
+
+// ServeHTTP implements http.handler interface and uses router ServeHTTP method
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
+
+type Response struct {
+	// Status is "ok" or "unavailable" when the database is not reachable
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+
+	DB *Stats `json:"db,omitempty"`
+}
+
+// Stats is sql.DBStats with durations in milliseconds
+type Stats struct {
+	MaxOpenConnections int `json:"maxOpenConnections"`
+	OpenConnections    int `json:"openConnections"`
+	InUse              int `json:"inUse"`
+	Idle               int `json:"idle"`
+
+	WaitCount         int64 `json:"waitCount"`
+	WaitDurationMs    int64 `json:"waitDurationMs"`
+	MaxIdleClosed     int64 `json:"maxIdleClosed"`
+	MaxIdleTimeClosed int64 `json:"maxIdleTimeClosed"`
+	MaxLifetimeClosed int64 `json:"maxLifetimeClosed"`
+}
+
+// NewStats converts database/sql stats to the response
+func NewStats(s sql.DBStats) *Stats {
+	return &Stats{
+		MaxOpenConnections: s.MaxOpenConnections,
+		OpenConnections:    s.OpenConnections,
+		InUse:              s.InUse,
+		Idle:               s.Idle,
+		WaitCount:          s.WaitCount,
+		WaitDurationMs:     s.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      s.MaxIdleClosed,
+		MaxIdleTimeClosed:  s.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  s.MaxLifetimeClosed,
+	}
+}
+
+// HandleHealth responds with 200 when the database is reachable and 503
+// otherwise. Pool stats are returned in both cases.
+func (s *Server) HandleHealth(w http.ResponseWriter, r *http.Request) error {
+	status := http.StatusOK
+	resp := Response{Status: "ok"}
+
+	if s.db != nil {
+		ctx, cancel := context.WithTimeout(r.Context(), pingTimeout)
+		defer cancel()
+
+		// Ping error may contain database host and user, it's only logged
+		err := s.db.Ping(ctx)
+		if err != nil {
+			log.Printf("health check failed to ping db: %+v", err)
+
+			status = http.StatusServiceUnavailable
+			resp.Status = "unavailable"
+			resp.Error = "database unavailable"
+		}
+
+		resp.DB = NewStats(s.db.Stats())
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return app.InternalError(errors.Wrap(err, "json.Marshal"))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	w.Write(body)
+	return nil
+}
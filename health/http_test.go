@@ -0,0 +1,87 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type testDB struct {
+	err   error
+	stats sql.DBStats
+}
+
+func (db testDB) Ping(ctx context.Context) error { return db.err }
+func (db testDB) Stats() sql.DBStats             { return db.stats }
+
+func TestHealthHandler(t *testing.T) {
+	stats := sql.DBStats{
+		MaxOpenConnections: 10,
+		OpenConnections:    3,
+		InUse:              1,
+		Idle:               2,
+		WaitCount:          5,
+		WaitDuration:       1500 * time.Millisecond,
+	}
+
+	tests := []struct {
+		name   string
+		db     DB
+		status int
+		resp   Response
+	}{
+		{
+			"NoDB",
+			nil,
+			http.StatusOK,
+			Response{Status: "ok"},
+		},
+		{
+			"Ok",
+			testDB{stats: stats},
+			http.StatusOK,
+			Response{Status: "ok", DB: NewStats(stats)},
+		},
+		{
+			"Unavailable",
+			testDB{err: errors.New("dial tcp db.internal:5432: connection refused"), stats: stats},
+			http.StatusServiceUnavailable,
+			Response{Status: "unavailable", Error: "database unavailable", DB: NewStats(stats)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewHTTP(tt.db)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			s.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rr.Code != tt.status {
+				t.Errorf("incorrect status, expected %v, got %v", tt.status, rr.Code)
+			}
+
+			var resp Response
+			err = json.Unmarshal(rr.Body.Bytes(), &resp)
+			if err != nil {
+				t.Fatalf("invalid response body %q: %v", rr.Body.String(), err)
+			}
+
+			if resp.Status != tt.resp.Status || resp.Error != tt.resp.Error {
+				t.Errorf("invalid status, expected %+v, got %+v", tt.resp, resp)
+			}
+
+			if (resp.DB == nil) != (tt.resp.DB == nil) || (resp.DB != nil && *resp.DB != *tt.resp.DB) {
+				t.Errorf("invalid db stats, expected %+v, got %+v", tt.resp.DB, resp.DB)
+			}
+		})
+	}
+}
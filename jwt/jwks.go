@@ -0,0 +1,26 @@
+package jwt
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+	"github.com/dzeban/conduit/transport"
+)
+
+// JWKSHandler serves public verification keys of the signer, so other
+// services can verify issued tokens. It's mounted at /.well-known/jwks.json.
+func JWKSHandler(signer *Signer) http.HandlerFunc {
+	return transport.WithError(func(w http.ResponseWriter, r *http.Request) error {
+		resp, err := json.Marshal(signer.JWKS())
+		if err != nil {
+			return app.InternalError(errors.Wrap(err, "json.Marshal"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(resp)
+		return nil
+	})
+}
@@ -1,9 +1,9 @@
 package jwt
 
 import (
-	"fmt"
+	"context"
 	"net/http"
-	"strings"
+	"strconv"
 
 	"github.com/pkg/errors"
 
@@ -12,7 +12,10 @@ import (
 )
 
 var (
-	errorJWTNoAuthorizationHeader = errors.New("no Authorization header")
+	errorJWTNoToken        = errors.New("no auth token")
+	errorJWTRevoked        = errors.New("token is revoked")
+	errorJWTInvalidSubject = errors.New("invalid token subject")
+	errorJWTUserNotFound   = errors.New("token user not found")
 )
 
 type AuthType int
@@ -22,28 +25,105 @@ const (
 	AuthTypeOptional
 )
 
-func Auth(secret []byte, typ AuthType) func(next http.Handler) http.Handler {
+// authConfig holds optional dependencies of the auth middleware
+type authConfig struct {
+	sources     []TokenSource
+	revocations RevocationStore
+	users       UserLookup
+}
+
+// AuthOption configures auth middleware
+type AuthOption func(*authConfig)
+
+// WithSources sets where the token is taken from. Sources are tried in order
+// and the first token found is used. By default the token is taken from
+// Authorization header with one of the DefaultSchemes.
+func WithSources(sources ...TokenSource) AuthOption {
+	return func(c *authConfig) {
+		c.sources = sources
+	}
+}
+
+// WithRevocations makes auth middleware reject revoked tokens. Store is
+// checked on every request, wrap it with NewRevocationCache to avoid that.
+func WithRevocations(store RevocationStore) AuthOption {
+	return func(c *authConfig) {
+		c.revocations = store
+	}
+}
+
+// WithUserLookup makes auth middleware load the user by the token subject
+// instead of trusting the user data in the token claims. Tokens of the users
+// that don't exist are rejected. Users are looked up on every request, wrap
+// lookup with NewUserCache to avoid that.
+func WithUserLookup(users UserLookup) AuthOption {
+	return func(c *authConfig) {
+		c.users = users
+	}
+}
+
+func Auth(signer *Signer, typ AuthType, opts ...AuthOption) func(next http.Handler) http.Handler {
+	config := authConfig{
+		sources: []TokenSource{FromHeader(DefaultSchemes...)},
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
 	return func(next http.Handler) http.Handler {
 		return transport.WithError(func(w http.ResponseWriter, r *http.Request) error {
-			authHeader, ok := r.Header["Authorization"]
-			if !ok {
+			token, err := tokenFromRequest(r, config.sources)
+			if err != nil {
+				return app.AuthError(errors.Wrap(err, "invalid auth token"))
+			}
+
+			if token == "" {
 				if typ == AuthTypeRequired {
-					return app.AuthError(errorJWTNoAuthorizationHeader)
+					return app.AuthError(errorJWTNoToken)
 				} else {
-					// If authorization header is absent pass to the next handler
-					// without updating context.
+					// If token is absent pass to the next handler without
+					// updating context.
 					next.ServeHTTP(w, r)
 					return nil
 				}
 			}
 
-			u, err := userFromJWT(authHeader[0], secret)
+			claims, err := Parse(token, signer)
 			if err != nil {
 				return app.AuthError(errors.Wrap(err, "invalid JWT"))
 			}
 
-			// Store user in context for the further reference
+			if config.revocations != nil {
+				revoked, err := config.revocations.IsTokenRevoked(r.Context(), claims.Id)
+				if err != nil {
+					return app.InternalError(errors.Wrap(err, "failed to check token revocation"))
+				}
+
+				if revoked {
+					return app.AuthError(errorJWTRevoked)
+				}
+			}
+
+			u := claims.User
+			if config.users != nil {
+				id, err := strconv.Atoi(claims.Subject)
+				if err != nil {
+					return app.AuthError(errorJWTInvalidSubject)
+				}
+
+				u, err = config.users.GetUserById(r.Context(), id)
+				if err != nil {
+					return app.InternalError(errors.Wrap(err, "failed to get token user"))
+				}
+
+				if u == nil {
+					return app.AuthError(errorJWTUserNotFound)
+				}
+			}
+
+			// Store user and claims in context for the further reference
 			authCtx := u.NewContext(r.Context())
+			authCtx = claims.NewContext(authCtx)
 
 			next.ServeHTTP(w, r.WithContext(authCtx))
 			return nil
@@ -51,23 +131,33 @@ func Auth(secret []byte, typ AuthType) func(next http.Handler) http.Handler {
 	}
 }
 
-// userFromJWT takes JWT from Authorization header, parses and
-// validates it and returns user. JWT is expected in "Token <token>" format.
-func userFromJWT(header string, secret []byte) (*app.User, error) {
-	tokenVals := strings.Split(header, " ")
+// tokenFromRequest returns token from the first source that has it
+func tokenFromRequest(r *http.Request, sources []TokenSource) (string, error) {
+	for _, source := range sources {
+		token, err := source(r)
+		if err != nil {
+			return "", err
+		}
 
-	if len(tokenVals) != 2 {
-		return nil, errors.New("invalid auth header format, expected 2 elements")
+		if token != "" {
+			return token, nil
+		}
 	}
 
-	if tokenVals[0] != "Token" {
-		return nil, fmt.Errorf("invalid auth header format, expected Token <token>, got %#v", header)
-	}
+	return "", nil
+}
 
-	claims, err := Parse(tokenVals[1], secret)
-	if err != nil {
-		return nil, errors.Wrap(err, "jwt parsing")
-	}
+// context.Context helpers
+type key int
+
+var contextKey key
+
+func (t *TokenClaims) NewContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey, t)
+}
 
-	return claims.User, nil
+// ClaimsFromContext returns claims of the token that authenticated request
+func ClaimsFromContext(ctx context.Context) (*TokenClaims, bool) {
+	t, ok := ctx.Value(contextKey).(*TokenClaims)
+	return t, ok
 }
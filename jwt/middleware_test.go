@@ -0,0 +1,95 @@
+package jwt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dzeban/conduit/app"
+)
+
+func TestAuthRevoked(t *testing.T) {
+	revocations := NewMemoryRevocations()
+
+	h := Auth(testSigner, AuthTypeRequired, WithRevocations(revocations))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+
+	token, err := New(testUser, testSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := Parse(token, testSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = revocations.RevokeToken(context.Background(), claims.Id, time.Unix(claims.ExpiresAt, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Add("Authorization", "Token "+token)
+
+	h.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("incorrect status for revoked token, expected %v, got %v", http.StatusUnauthorized, rr.Code)
+	}
+}
+
+type testUsers map[int]*app.User
+
+func (u testUsers) GetUserById(ctx context.Context, id int) (*app.User, error) {
+	return u[id], nil
+}
+
+func TestAuthUserLookup(t *testing.T) {
+	// Token carries stale user data, the current one is in the store
+	current := &app.User{Id: testUser.Id, Name: "current", Email: "current@example.com"}
+	users := testUsers{current.Id: current}
+
+	var contextUser *app.User
+	h := Auth(testSigner, AuthTypeRequired, WithUserLookup(users))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contextUser, _ = app.UserFromContext(r.Context())
+		}),
+	)
+
+	tests := []struct {
+		name   string
+		user   *app.User
+		status int
+	}{
+		{"Stale", testUser, http.StatusOK},
+		{"Deleted", &app.User{Id: 2, Name: "deleted", Email: "deleted@example.com"}, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := New(tt.user, testSigner)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Add("Authorization", "Token "+token)
+
+			h.ServeHTTP(rr, req)
+
+			if rr.Code != tt.status {
+				t.Errorf("incorrect status, expected %v, got %v", tt.status, rr.Code)
+			}
+
+			if tt.status == http.StatusOK && contextUser.Name != current.Name {
+				t.Errorf("user is not loaded from the store, got %+v", contextUser)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// How long revocation checks are cached
+	revocationCacheTTL = 5 * time.Second
+
+	// Max number of cached revocation checks
+	revocationCacheSize = 1024
+)
+
+// RevocationStore keeps ids (jti) of revoked tokens. Tokens are kept until
+// they expire because expired tokens are rejected anyway.
+type RevocationStore interface {
+	RevokeToken(ctx context.Context, id string, expires time.Time) error
+	IsTokenRevoked(ctx context.Context, id string) (bool, error)
+}
+
+// MemoryRevocations is RevocationStore that keeps revoked tokens in memory.
+// It's suitable only for a single server instance because revocations are
+// neither shared nor persisted.
+type MemoryRevocations struct {
+	mu     sync.Mutex
+	tokens map[string]time.Time
+}
+
+func NewMemoryRevocations() *MemoryRevocations {
+	return &MemoryRevocations{
+		tokens: make(map[string]time.Time),
+	}
+}
+
+func (m *MemoryRevocations) RevokeToken(ctx context.Context, id string, expires time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Forget expired tokens
+	now := time.Now()
+	for id, exp := range m.tokens {
+		if now.After(exp) {
+			delete(m.tokens, id)
+		}
+	}
+
+	m.tokens[id] = expires
+	return nil
+}
+
+func (m *MemoryRevocations) IsTokenRevoked(ctx context.Context, id string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.tokens[id]
+	return ok, nil
+}
+
+type revocationEntry struct {
+	revoked bool
+	until   time.Time
+}
+
+// RevocationCache is RevocationStore that caches checks of the underlying
+// store for a short time, so auth middleware doesn't query the store on every
+// request. Tokens revoked through the cache are rejected immediately,
+// revocations made elsewhere become visible after the cached check expires.
+type RevocationCache struct {
+	store RevocationStore
+
+	mu      sync.Mutex
+	entries map[string]revocationEntry
+}
+
+func NewRevocationCache(store RevocationStore) *RevocationCache {
+	return &RevocationCache{
+		store:   store,
+		entries: make(map[string]revocationEntry),
+	}
+}
+
+func (c *RevocationCache) RevokeToken(ctx context.Context, id string, expires time.Time) error {
+	err := c.store.RevokeToken(ctx, id, expires)
+	if err != nil {
+		return err
+	}
+
+	c.set(id, true)
+	return nil
+}
+
+func (c *RevocationCache) IsTokenRevoked(ctx context.Context, id string) (bool, error) {
+	c.mu.Lock()
+	e, ok := c.entries[id]
+	c.mu.Unlock()
+
+	if ok && time.Now().Before(e.until) {
+		return e.revoked, nil
+	}
+
+	revoked, err := c.store.IsTokenRevoked(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	c.set(id, revoked)
+	return revoked, nil
+}
+
+func (c *RevocationCache) set(id string, revoked bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+
+	// Keep the cache small: drop stale entries when it's full and start over
+	// if there are no stale entries
+	if len(c.entries) >= revocationCacheSize {
+		for id, e := range c.entries {
+			if now.After(e.until) {
+				delete(c.entries, id)
+			}
+		}
+
+		if len(c.entries) >= revocationCacheSize {
+			c.entries = make(map[string]revocationEntry)
+		}
+	}
+
+	c.entries[id] = revocationEntry{
+		revoked: revoked,
+		until:   now.Add(revocationCacheTTL),
+	}
+}
@@ -0,0 +1,41 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRevocationCache(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryRevocations()
+	cache := NewRevocationCache(store)
+
+	revoked, err := cache.IsTokenRevoked(ctx, "id")
+	if err != nil || revoked {
+		t.Fatalf("unexpected revocation check result %v, error %v", revoked, err)
+	}
+
+	// Revocation through the cache is visible immediately
+	err = cache.RevokeToken(ctx, "id", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revoked, err = cache.IsTokenRevoked(ctx, "id")
+	if err != nil || !revoked {
+		t.Errorf("token revoked through the cache is not revoked, error %v", err)
+	}
+
+	// Revocation made directly in the store is hidden by the cached check
+	_, _ = cache.IsTokenRevoked(ctx, "other")
+	err = store.RevokeToken(ctx, "other", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revoked, err = cache.IsTokenRevoked(ctx, "other")
+	if err != nil || revoked {
+		t.Errorf("expected cached check result, got revoked %v, error %v", revoked, err)
+	}
+}
@@ -0,0 +1,242 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/pkg/errors"
+)
+
+// Supported signing algorithms
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// Key is a key used to sign or verify tokens. Id is put to the kid token
+// header to find the verification key among the others.
+type Key struct {
+	Id     string
+	Method jwt.SigningMethod
+
+	// SignKey is nil for the keys used only for verification
+	SignKey   interface{}
+	VerifyKey interface{}
+}
+
+// MinHMACKeyLen is the minimal length of HS256 secret. RFC 7518 requires key
+// of the hash output size, shorter secrets can be brute-forced offline from
+// any issued token.
+const MinHMACKeyLen = 32
+
+// NewHMACKey returns HS256 key with the secret. Empty or short secret is an
+// error because anyone could forge tokens with it.
+func NewHMACKey(id string, secret []byte) (*Key, error) {
+	if len(secret) < MinHMACKeyLen {
+		return nil, fmt.Errorf("HS256 secret must be at least %d bytes, got %d", MinHMACKeyLen, len(secret))
+	}
+
+	return &Key{
+		Id:        id,
+		Method:    jwt.SigningMethodHS256,
+		SignKey:   secret,
+		VerifyKey: secret,
+	}, nil
+}
+
+// LoadKey reads key for the algorithm from file. For HS256 the file contents
+// is the secret. For RS256 and EdDSA the file is PEM-encoded private key or
+// public key, the latter can only be used for verification.
+func LoadKey(id, alg, path string) (*Key, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read key file")
+	}
+
+	switch alg {
+	case AlgHS256:
+		return NewHMACKey(id, []byte(strings.TrimSpace(string(data))))
+
+	case AlgRS256:
+		key := &Key{Id: id, Method: jwt.SigningMethodRS256}
+
+		private, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err == nil {
+			key.SignKey = private
+			key.VerifyKey = &private.PublicKey
+			return key, nil
+		}
+
+		public, err := jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse RSA key")
+		}
+		key.VerifyKey = public
+		return key, nil
+
+	case AlgEdDSA:
+		key := &Key{Id: id, Method: jwt.SigningMethodEdDSA}
+
+		private, err := jwt.ParseEdPrivateKeyFromPEM(data)
+		if err == nil {
+			edPrivate := private.(ed25519.PrivateKey)
+			key.SignKey = edPrivate
+			key.VerifyKey = edPrivate.Public()
+			return key, nil
+		}
+
+		public, err := jwt.ParseEdPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse EdDSA key")
+		}
+		key.VerifyKey = public
+		return key, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+}
+
+// Signer signs tokens with the current key and verifies them with any of the
+// known keys. Keeping the previous keys for verification allows to rotate
+// signing key without invalidating issued tokens.
+type Signer struct {
+	current *Key
+	keys    map[string]*Key
+}
+
+// NewSigner creates signer that signs tokens with the current key. Current
+// key and the others are used for verification.
+func NewSigner(current *Key, others ...*Key) (*Signer, error) {
+	if current == nil || current.SignKey == nil {
+		return nil, errors.New("current key can't sign tokens")
+	}
+
+	s := &Signer{
+		current: current,
+		keys:    make(map[string]*Key),
+	}
+
+	for _, key := range append([]*Key{current}, others...) {
+		if _, ok := s.keys[key.Id]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", key.Id)
+		}
+		s.keys[key.Id] = key
+	}
+
+	return s, nil
+}
+
+// NewHMACSigner creates signer with a single HS256 key without id. It's meant
+// for tests and panics when the secret is too short.
+func NewHMACSigner(secret []byte) *Signer {
+	key, err := NewHMACKey("", secret)
+	if err != nil {
+		panic(err)
+	}
+
+	s, err := NewSigner(key)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// Sign returns signed token with the claims
+func (s *Signer) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.current.Method, claims)
+	if s.current.Id != "" {
+		token.Header["kid"] = s.current.Id
+	}
+
+	return token.SignedString(s.current.SignKey)
+}
+
+// Verify parses token into claims and verifies it. Verification key is found
+// by kid header and the token algorithm must match the key algorithm exactly.
+func (s *Signer) Verify(token string, claims jwt.Claims) error {
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+
+		key, ok := s.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+
+		// Never trust the algorithm from token header, otherwise public key
+		// may be used as HMAC secret or signature may be skipped with "none"
+		if t.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing algorithm %q", t.Method.Alg())
+		}
+
+		return key.VerifyKey, nil
+	})
+
+	return err
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+
+	// RSA public key
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// Ed25519 public key
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is a set of JSON Web Keys
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns public verification keys. HMAC secrets are never published.
+func (s *Signer) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+
+	for _, key := range s.keys {
+		switch k := key.VerifyKey.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "RSA",
+				Kid: key.Id,
+				Alg: key.Method.Alg(),
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+			})
+
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "OKP",
+				Kid: key.Id,
+				Alg: key.Method.Alg(),
+				Use: "sig",
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(k),
+			})
+		}
+	}
+
+	// Keep keys order stable
+	sort.Slice(jwks.Keys, func(i, j int) bool {
+		return jwks.Keys[i].Kid < jwks.Keys[j].Kid
+	})
+
+	return jwks
+}
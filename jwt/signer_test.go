@@ -0,0 +1,238 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+)
+
+func newRSAKey(t *testing.T, id string) *Key {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Key{Id: id, Method: jwt.SigningMethodRS256, SignKey: private, VerifyKey: &private.PublicKey}
+}
+
+func newEdDSAKey(t *testing.T, id string) *Key {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Key{Id: id, Method: jwt.SigningMethodEdDSA, SignKey: private, VerifyKey: public}
+}
+
+func newHMACKey(t *testing.T, id string, secret []byte) *Key {
+	key, err := NewHMACKey(id, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func TestSigner(t *testing.T) {
+	tests := []struct {
+		name string
+		key  *Key
+	}{
+		{"HS256", newHMACKey(t, "hmac", []byte("test-secret-of-at-least-32-bytes"))},
+		{"RS256", newRSAKey(t, "rsa")},
+		{"EdDSA", newEdDSAKey(t, "ed")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := NewSigner(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			token, err := New(testUser, signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			claims, err := Parse(token, signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if claims.User.Email != testUser.Email {
+				t.Errorf("invalid claims %+v", claims)
+			}
+		})
+	}
+}
+
+func TestSignerRotation(t *testing.T) {
+	oldKey := newEdDSAKey(t, "old")
+	newKey := newRSAKey(t, "new")
+
+	oldSigner, err := NewSigner(oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldToken, err := New(testUser, oldSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Old key is kept for verification only
+	signer, err := NewSigner(newKey, &Key{Id: oldKey.Id, Method: oldKey.Method, VerifyKey: oldKey.VerifyKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Parse(oldToken, signer)
+	if err != nil {
+		t.Errorf("token signed with the old key is not verified: %v", err)
+	}
+
+	jwks := signer.JWKS()
+	if len(jwks.Keys) != 2 || jwks.Keys[0].Kid != "new" || jwks.Keys[1].Kid != "old" {
+		t.Errorf("invalid JWKS %+v", jwks)
+	}
+}
+
+func TestSignerStrictAlg(t *testing.T) {
+	rsaKey := newRSAKey(t, "rsa")
+	signer, err := NewSigner(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Token signed with HS256 using RSA public key as a secret
+	publicDER, err := x509.MarshalPKIXPublicKey(rsaKey.VerifyKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+
+	forger, err := NewSigner(newHMACKey(t, "rsa", publicPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := New(testUser, forger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Parse(token, signer)
+	if err == nil {
+		t.Errorf("token with unexpected algorithm is verified")
+	}
+
+	// Token signed with unknown key
+	other, err := NewSigner(newRSAKey(t, "other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err = New(testUser, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Parse(token, signer)
+	if err == nil {
+		t.Errorf("token with unknown key id is verified")
+	}
+}
+
+func TestLoadKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jwt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privatePath := filepath.Join(dir, "private.pem")
+	err = ioutil.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicPath := filepath.Join(dir, "public.pem")
+	err = ioutil.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signKey, err := LoadKey("sign", AlgEdDSA, privatePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifyKey, err := LoadKey("verify", AlgEdDSA, publicPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifyKey.SignKey != nil {
+		t.Errorf("public key can sign tokens")
+	}
+
+	_, err = NewSigner(verifyKey)
+	if err == nil {
+		t.Errorf("signer is created with verification only key")
+	}
+
+	_, err = NewSigner(signKey, verifyKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHMACKeyLength(t *testing.T) {
+	for _, secret := range []string{"", "short"} {
+		_, err := NewHMACKey("hmac", []byte(secret))
+		if err == nil {
+			t.Errorf("key is created with %d bytes secret", len(secret))
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "jwt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "secret")
+	err = ioutil.WriteFile(path, []byte("  \n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = LoadKey("hmac", AlgHS256, path)
+	if err == nil {
+		t.Errorf("key is loaded from file with empty secret")
+	}
+}
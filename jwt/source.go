@@ -0,0 +1,60 @@
+package jwt
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TokenSource extracts token from the request. It returns empty string if
+// the request has no token in the source and error if the token is malformed.
+type TokenSource func(r *http.Request) (string, error)
+
+// DefaultSchemes are Authorization header schemes accepted by default
+var DefaultSchemes = []string{"Token", "Bearer"}
+
+// FromHeader returns source that takes token from the Authorization header in
+// "<scheme> <token>" format. Schemes are matched case-insensitively.
+func FromHeader(schemes ...string) TokenSource {
+	return func(r *http.Request) (string, error) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			return "", nil
+		}
+
+		tokenVals := strings.Fields(header)
+		if len(tokenVals) != 2 {
+			return "", fmt.Errorf("invalid auth header format, expected <scheme> <token>, got %#v", header)
+		}
+
+		for _, scheme := range schemes {
+			if strings.EqualFold(tokenVals[0], scheme) {
+				return tokenVals[1], nil
+			}
+		}
+
+		return "", fmt.Errorf("invalid auth header scheme %#v, expected one of %v", tokenVals[0], schemes)
+	}
+}
+
+// FromCookie returns source that takes token from the cookie with the name.
+// Browsers send cookies with requests made by any site, so the cookie is
+// accepted only for GET and HEAD requests to prevent cross-site request
+// forgery. Requests that modify data must use the Authorization header.
+func FromCookie(name string) TokenSource {
+	return func(r *http.Request) (string, error) {
+		cookie, err := r.Cookie(name)
+		if err == http.ErrNoCookie {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			return "", fmt.Errorf("token cookie is not accepted for %s requests, use Authorization header", r.Method)
+		}
+
+		return cookie.Value, nil
+	}
+}
@@ -0,0 +1,91 @@
+package jwt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		token  string
+		err    bool
+	}{
+		{"Absent", "", "", false},
+		{"Token", "Token abc", "abc", false},
+		{"Bearer", "Bearer abc", "abc", false},
+		{"CaseInsensitive", "bEARER abc", "abc", false},
+		{"UnknownScheme", "Basic abc", "", true},
+		{"NoToken", "Bearer", "", true},
+		{"ExtraValues", "Bearer abc def", "", true},
+	}
+
+	source := FromHeader(DefaultSchemes...)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			token, err := source(req)
+			if (err != nil) != tt.err {
+				t.Errorf("unexpected error %v", err)
+			}
+
+			if token != tt.token {
+				t.Errorf("invalid token, expected %q, got %q", tt.token, token)
+			}
+		})
+	}
+}
+
+func TestAuthSources(t *testing.T) {
+	token, err := New(testUser, testSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := Auth(testSigner, AuthTypeRequired, WithSources(FromHeader("Bearer"), FromCookie("token")))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+
+	tests := []struct {
+		name   string
+		method string
+		header string
+		cookie string
+		status int
+	}{
+		{"None", http.MethodGet, "", "", http.StatusUnauthorized},
+		{"Header", http.MethodGet, "Bearer " + token, "", http.StatusOK},
+		{"DisabledScheme", http.MethodGet, "Token " + token, "", http.StatusUnauthorized},
+		{"Cookie", http.MethodGet, "", token, http.StatusOK},
+		{"CookieHead", http.MethodHead, "", token, http.StatusOK},
+		{"CookiePost", http.MethodPost, "", token, http.StatusUnauthorized},
+		{"CookieDelete", http.MethodDelete, "", token, http.StatusUnauthorized},
+		{"HeaderPostWithCookie", http.MethodPost, "Bearer " + token, token, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "token", Value: tt.cookie})
+			}
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+
+			if rr.Code != tt.status {
+				t.Errorf("incorrect status, expected %v, got %v", tt.status, rr.Code)
+			}
+		})
+	}
+}
@@ -1,17 +1,52 @@
 package jwt
 
 import (
+	"strconv"
+	"time"
+
+	"github.com/dchest/uniuri"
 	"github.com/golang-jwt/jwt"
 	"github.com/pkg/errors"
 
 	"github.com/dzeban/conduit/app"
 )
 
+const (
+	// DefaultLifetime is the lifetime of tokens created with New
+	DefaultLifetime = 24 * time.Hour
+
+	// Length of random token id
+	idLen = 32
+)
+
+// TokenClaims is a JWT payload. Besides the user it holds standard claims:
+// subject is the user id, id (jti) is unique for every token, issued at and
+// expires at define token lifetime.
 type TokenClaims struct {
 	User *app.User `json:"user"`
+	jwt.StandardClaims
 }
 
 func (t TokenClaims) Valid() error {
+	// Check expiration, issued at and not before claims
+	err := t.StandardClaims.Valid()
+	if err != nil {
+		return err
+	}
+
+	// Tokens without expiration would be valid forever
+	if t.ExpiresAt == 0 {
+		return errors.New("expiration time is empty")
+	}
+
+	if t.Subject == "" {
+		return errors.New("subject is empty")
+	}
+
+	if t.Id == "" {
+		return errors.New("token id is empty")
+	}
+
 	if t.User == nil {
 		return errors.New("empty user")
 	}
@@ -27,17 +62,32 @@ func (t TokenClaims) Valid() error {
 	return nil
 }
 
-func New(user *app.User, secret []byte) (string, error) {
-	return jwt.
-		NewWithClaims(jwt.SigningMethodHS256, TokenClaims{user}).
-		SignedString(secret)
+// New creates token for the user with the default lifetime
+func New(user *app.User, signer *Signer) (string, error) {
+	return NewWithLifetime(user, signer, DefaultLifetime)
+}
+
+// NewWithLifetime creates token for the user that expires after lifetime
+func NewWithLifetime(user *app.User, signer *Signer, lifetime time.Duration) (string, error) {
+	now := time.Now()
+
+	claims := TokenClaims{
+		User: user,
+		StandardClaims: jwt.StandardClaims{
+			Id:        uniuri.NewLen(idLen),
+			Subject:   strconv.Itoa(user.Id),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(lifetime).Unix(),
+		},
+	}
+
+	return signer.Sign(claims)
 }
 
-func Parse(token string, secret []byte) (*TokenClaims, error) {
+// Parse verifies token with the signer and returns its claims
+func Parse(token string, signer *Signer) (*TokenClaims, error) {
 	var tc TokenClaims
-	_, err := jwt.ParseWithClaims(token, &tc, func(t *jwt.Token) (interface{}, error) {
-		return secret, nil
-	})
+	err := signer.Verify(token, &tc)
 	if err != nil {
 		return nil, errors.Wrap(err, "jwt parsing error")
 	}
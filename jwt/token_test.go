@@ -0,0 +1,41 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dzeban/conduit/app"
+)
+
+var (
+	testSigner = NewHMACSigner([]byte("test-secret-of-at-least-32-bytes"))
+	testUser   = &app.User{Id: 1, Name: "test", Email: "test@example.com"}
+)
+
+func TestParse(t *testing.T) {
+	token, err := New(testUser, testSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := Parse(token, testSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.Subject != "1" || claims.Id == "" || claims.IssuedAt == 0 || claims.ExpiresAt == 0 {
+		t.Errorf("invalid standard claims %+v", claims.StandardClaims)
+	}
+}
+
+func TestParseExpired(t *testing.T) {
+	token, err := NewWithLifetime(testUser, testSigner, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Parse(token, testSigner)
+	if err == nil {
+		t.Errorf("expired token is parsed without error")
+	}
+}
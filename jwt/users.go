@@ -0,0 +1,100 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dzeban/conduit/app"
+)
+
+const (
+	// How long looked up users are cached
+	userCacheTTL = 5 * time.Second
+
+	// Max number of cached users
+	userCacheSize = 1024
+)
+
+// UserLookup finds user by id. It returns nil if there is no such user.
+// user.Store satisfies this interface.
+type UserLookup interface {
+	GetUserById(ctx context.Context, id int) (*app.User, error)
+}
+
+type userEntry struct {
+	user  *app.User
+	until time.Time
+}
+
+// UserCache is UserLookup that caches users found by the underlying lookup
+// for a short time, so auth middleware doesn't query the store on every
+// request. Users that don't exist are cached too.
+type UserCache struct {
+	users UserLookup
+
+	mu      sync.Mutex
+	entries map[int]userEntry
+}
+
+func NewUserCache(users UserLookup) *UserCache {
+	return &UserCache{
+		users:   users,
+		entries: make(map[int]userEntry),
+	}
+}
+
+func (c *UserCache) GetUserById(ctx context.Context, id int) (*app.User, error) {
+	c.mu.Lock()
+	e, ok := c.entries[id]
+	c.mu.Unlock()
+
+	if ok && time.Now().Before(e.until) {
+		return copyUser(e.user), nil
+	}
+
+	u, err := c.users.GetUserById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	c.set(id, u)
+	return copyUser(u), nil
+}
+
+func (c *UserCache) set(id int, u *app.User) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+
+	// Keep the cache small: drop stale entries when it's full and start over
+	// if there are no stale entries
+	if len(c.entries) >= userCacheSize {
+		for id, e := range c.entries {
+			if now.After(e.until) {
+				delete(c.entries, id)
+			}
+		}
+
+		if len(c.entries) >= userCacheSize {
+			c.entries = make(map[int]userEntry)
+		}
+	}
+
+	c.entries[id] = userEntry{
+		user:  copyUser(u),
+		until: now.Add(userCacheTTL),
+	}
+}
+
+// copyUser returns a copy of the user so cached users can't be modified by
+// the callers
+func copyUser(u *app.User) *app.User {
+	if u == nil {
+		return nil
+	}
+
+	user := *u
+	return &user
+}
@@ -0,0 +1,323 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+	"github.com/dzeban/conduit/search"
+)
+
+var errorArticleSlugExists = errors.New("article with this slug exists")
+
+// CreateArticle adds new article and sets its id and version
+func (s *Store) CreateArticle(ctx context.Context, a *app.Article) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if _, ok := s.articleBySlug[a.Slug]; ok {
+		return errorArticleSlugExists
+	}
+
+	s.lastArticleId++
+	a.Id = s.lastArticleId
+	a.Version = 1
+
+	s.articles[a.Id] = stored(a)
+	s.articleBySlug[a.Slug] = a.Id
+
+	return nil
+}
+
+// GetArticle returns article by slug or nil if there is no such article.
+// Viewer is optional, when it's set the following and favorited fields are
+// filled for it.
+func (s *Store) GetArticle(ctx context.Context, slug string, viewer *app.Profile) (*app.Article, error) {
+	if err := s.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	id, ok := s.articleBySlug[slug]
+	if !ok {
+		return nil, nil
+	}
+
+	return s.article(id, viewer), nil
+}
+
+// ListArticles returns articles matching the filter. Articles are sorted from
+// the newest to the oldest, search results are sorted by relevance first.
+func (s *Store) ListArticles(ctx context.Context, f *app.ArticleListFilter) ([]*app.Article, error) {
+	if err := s.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	viewer := app.ProfileFromUser(f.CurrentUser)
+	terms := search.Terms(f.Query)
+
+	var articles []*app.Article
+	for _, id := range s.filter(f) {
+		a := s.article(id, viewer)
+		if f.After != nil && !isAfter(a, f.After) {
+			continue
+		}
+
+		if len(terms) > 0 {
+			a.Snippet = search.Snippet(a.Body, terms)
+		}
+
+		articles = append(articles, a)
+	}
+
+	if f.Offset >= uint64(len(articles)) {
+		return nil, nil
+	}
+	articles = articles[f.Offset:]
+
+	if f.Limit < uint64(len(articles)) {
+		articles = articles[:f.Limit]
+	}
+
+	return articles, nil
+}
+
+// CountArticles returns total number of articles matching the filter
+// regardless of its limit, offset and cursor
+func (s *Store) CountArticles(ctx context.Context, f *app.ArticleListFilter) (int, error) {
+	if err := s.rlock(ctx); err != nil {
+		return 0, err
+	}
+	defer s.mu.RUnlock()
+
+	return len(s.filter(f)), nil
+}
+
+// filter returns sorted ids of articles matching the filter ignoring its
+// limit, offset and cursor. It expects the lock to be held.
+func (s *Store) filter(f *app.ArticleListFilter) []int {
+	terms := search.Terms(f.Query)
+	ranks := make(map[int]int)
+
+	var ids []int
+	for id, a := range s.articles {
+		if f.FeedOf != nil && !s.followers[f.FeedOf.Id][a.Author.Id] {
+			continue
+		}
+
+		if f.Author != nil && a.Author.Id != f.Author.Id {
+			continue
+		}
+
+		if f.Tag != "" && !hasTag(&a, f.Tag) {
+			continue
+		}
+
+		if f.Favorited != nil && !s.favorites[id][f.Favorited.Id] {
+			continue
+		}
+
+		if len(terms) > 0 {
+			ranks[id] = search.Rank(a.Title, a.Description, a.Body, terms)
+			if ranks[id] == 0 {
+				continue
+			}
+		}
+
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		if ranks[ids[i]] != ranks[ids[j]] {
+			return ranks[ids[i]] > ranks[ids[j]]
+		}
+
+		ai, aj := s.articles[ids[i]], s.articles[ids[j]]
+		if ai.Created.Equal(aj.Created) {
+			return ai.Id > aj.Id
+		}
+		return ai.Created.After(aj.Created)
+	})
+
+	return ids
+}
+
+// UpdateArticle modifies non-empty fields and replaces tags of the article
+// only if its version matches a.Version. It returns
+// app.ErrorArticleVersionMismatch otherwise.
+func (s *Store) UpdateArticle(ctx context.Context, a *app.Article) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	stored, ok := s.articles[a.Id]
+	if !ok || stored.Version != a.Version {
+		return app.ErrorArticleVersionMismatch
+	}
+
+	if a.Title != "" {
+		stored.Title = a.Title
+	}
+	if a.Description != "" {
+		stored.Description = a.Description
+	}
+	if a.Body != "" {
+		stored.Body = a.Body
+	}
+	if !a.Updated.IsZero() {
+		stored.Updated = a.Updated
+	}
+
+	stored.TagList = sortedTags(a.TagList)
+	stored.Version++
+	a.Version = stored.Version
+
+	s.articles[a.Id] = stored
+
+	return nil
+}
+
+// DeleteArticle deletes article with its comments and favorites
+func (s *Store) DeleteArticle(ctx context.Context, id int) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	a, ok := s.articles[id]
+	if !ok {
+		return nil
+	}
+
+	delete(s.articles, id)
+	delete(s.articleBySlug, a.Slug)
+	delete(s.favorites, id)
+
+	for commentId, c := range s.comments {
+		if c.ArticleId == id {
+			delete(s.comments, commentId)
+		}
+	}
+
+	return nil
+}
+
+// FavoriteArticle adds article to the user favorites. Favoriting twice is not
+// an error.
+func (s *Store) FavoriteArticle(ctx context.Context, user *app.Profile, a *app.Article) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if _, ok := s.articles[a.Id]; !ok {
+		return errors.Errorf("article %d not found", a.Id)
+	}
+
+	if s.favorites[a.Id] == nil {
+		s.favorites[a.Id] = make(map[int]bool)
+	}
+	s.favorites[a.Id][user.Id] = true
+
+	return nil
+}
+
+// UnfavoriteArticle removes article from the user favorites if it's there
+func (s *Store) UnfavoriteArticle(ctx context.Context, user *app.Profile, a *app.Article) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	delete(s.favorites[a.Id], user.Id)
+	return nil
+}
+
+// ListTags returns sorted names of all tags that are used by at least one
+// article
+func (s *Store) ListTags(ctx context.Context) ([]string, error) {
+	if err := s.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	tags := []string{}
+	for _, a := range s.articles {
+		for _, tag := range a.TagList {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	sort.Strings(tags)
+
+	return tags, nil
+}
+
+// article returns a copy of the stored article with author, favorites and
+// following fields filled for the viewer. It expects the lock to be held.
+func (s *Store) article(id int, viewer *app.Profile) *app.Article {
+	a := s.articles[id]
+	a.Author = s.profile(a.Author.Id, viewer)
+	a.TagList = append([]string{}, a.TagList...)
+	a.FavoritesCount = len(s.favorites[id])
+	a.Favorited = viewer != nil && s.favorites[id][viewer.Id]
+
+	return &a
+}
+
+// stored returns a copy of the article to keep in the store. Only author id is
+// kept because the rest of the author fields are taken from users.
+func stored(a *app.Article) app.Article {
+	article := *a
+	article.Author = app.Profile{Id: a.Author.Id}
+	article.TagList = sortedTags(a.TagList)
+	article.Favorited = false
+	article.FavoritesCount = 0
+	article.Snippet = ""
+
+	return article
+}
+
+// sortedTags returns sorted copy of tags without duplicates like the tags
+// table with the unique name does
+func sortedTags(tags []string) []string {
+	seen := make(map[string]bool)
+	sorted := []string{}
+	for _, tag := range tags {
+		if !seen[tag] {
+			seen[tag] = true
+			sorted = append(sorted, tag)
+		}
+	}
+
+	sort.Strings(sorted)
+	return sorted
+}
+
+func hasTag(a *app.Article, tag string) bool {
+	for _, t := range a.TagList {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// isAfter tells whether article goes after cursor position in the list sorted
+// by creation time and id in descending order
+func isAfter(a *app.Article, c *app.ArticleCursor) bool {
+	if a.Created.Equal(c.Created) {
+		return a.Id < c.Id
+	}
+	return a.Created.Before(c.Created)
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+)
+
+// CreateComment adds new comment and sets its id
+func (s *Store) CreateComment(ctx context.Context, c *app.Comment) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if _, ok := s.articles[c.ArticleId]; !ok {
+		return errors.Errorf("article %d not found", c.ArticleId)
+	}
+
+	s.lastCommentId++
+	c.Id = s.lastCommentId
+
+	comment := *c
+	comment.Author = app.Profile{Id: c.Author.Id}
+	s.comments[c.Id] = comment
+
+	return nil
+}
+
+// GetComment returns comment by id or nil if there is no such comment.
+// Following field of the author is not set.
+func (s *Store) GetComment(ctx context.Context, id int) (*app.Comment, error) {
+	if err := s.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	c, ok := s.comments[id]
+	if !ok {
+		return nil, nil
+	}
+
+	c.Author = s.profile(c.Author.Id, nil)
+	return &c, nil
+}
+
+// ListComments returns comments of the article sorted from the oldest to the
+// newest. Viewer is optional, when it's set the following field of comment
+// authors is filled for it.
+func (s *Store) ListComments(ctx context.Context, articleId int, viewer *app.Profile) ([]*app.Comment, error) {
+	if err := s.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	comments := []*app.Comment{}
+	for _, c := range s.comments {
+		if c.ArticleId != articleId {
+			continue
+		}
+
+		c := c
+		c.Author = s.profile(c.Author.Id, viewer)
+		comments = append(comments, &c)
+	}
+
+	sort.Slice(comments, func(i, j int) bool {
+		if comments[i].Created.Equal(comments[j].Created) {
+			return comments[i].Id < comments[j].Id
+		}
+		return comments[i].Created.Before(comments[j].Created)
+	})
+
+	return comments, nil
+}
+
+// DeleteComment deletes comment by id
+func (s *Store) DeleteComment(ctx context.Context, id int) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	delete(s.comments, id)
+	return nil
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/dzeban/conduit/app"
+)
+
+// GetProfile returns profile by username. Follower is optional, when it's set
+// the following field is filled for it.
+func (s *Store) GetProfile(ctx context.Context, username string, follower *app.Profile) (*app.Profile, error) {
+	if err := s.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	id, ok := s.userByName[username]
+	if !ok {
+		return nil, app.ErrorProfileNotFound
+	}
+
+	p := s.profile(id, follower)
+	return &p, nil
+}
+
+// FollowProfile makes follower follow the followee. Following twice is not an
+// error.
+func (s *Store) FollowProfile(ctx context.Context, follower, followee *app.Profile) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if s.followers[follower.Id] == nil {
+		s.followers[follower.Id] = make(map[int]bool)
+	}
+	s.followers[follower.Id][followee.Id] = true
+
+	return nil
+}
+
+// UnfollowProfile removes follow relationship if it exists
+func (s *Store) UnfollowProfile(ctx context.Context, follower, followee *app.Profile) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	delete(s.followers[follower.Id], followee.Id)
+	return nil
+}
@@ -0,0 +1,99 @@
+// Package memory implements the app stores in memory. It's meant for demos
+// and frontend development where running Postgres is a burden. Everything is
+// lost on restart.
+//
+// Store follows the semantics of the postgres store: ids are generated on
+// insert, emails, usernames and slugs are unique, deleting an article deletes
+// its comments and favorites, lists are sorted the same way. Full-text search
+// is approximated with the search package.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dzeban/conduit/app"
+)
+
+// Store keeps all of the app data in maps guarded by a single mutex. Values
+// are copied in and out of the store, so callers can't modify stored data.
+type Store struct {
+	mu sync.RWMutex
+
+	users       map[int]app.User
+	userByEmail map[string]int
+	userByName  map[string]int
+
+	articles      map[int]app.Article
+	articleBySlug map[string]int
+
+	comments map[int]app.Comment
+
+	// followers maps follower id to the set of followee ids
+	followers map[int]map[int]bool
+
+	// favorites maps article id to the set of user ids that favorited it
+	favorites map[int]map[int]bool
+
+	refreshTokens map[string]app.RefreshToken
+
+	// revoked maps revoked token id to its expiration time
+	revoked map[string]time.Time
+
+	// Last generated ids
+	lastUserId    int
+	lastArticleId int
+	lastCommentId int
+}
+
+// NewStore creates empty store
+func NewStore() *Store {
+	return &Store{
+		users:         make(map[int]app.User),
+		userByEmail:   make(map[string]int),
+		userByName:    make(map[string]int),
+		articles:      make(map[int]app.Article),
+		articleBySlug: make(map[string]int),
+		comments:      make(map[int]app.Comment),
+		followers:     make(map[int]map[int]bool),
+		favorites:     make(map[int]map[int]bool),
+		refreshTokens: make(map[string]app.RefreshToken),
+		revoked:       make(map[string]time.Time),
+	}
+}
+
+// Close does nothing, it's here to be interchangeable with database stores
+func (s *Store) Close() error {
+	return nil
+}
+
+// lock takes write lock unless context is already done
+func (s *Store) lock(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	return nil
+}
+
+// rlock takes read lock unless context is already done
+func (s *Store) rlock(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	return nil
+}
+
+// profile returns profile of user with id. Following is set when viewer
+// follows the user. It expects the lock to be held.
+func (s *Store) profile(id int, viewer *app.Profile) app.Profile {
+	u := s.users[id]
+	p := *app.ProfileFromUser(&u)
+	p.Following = viewer != nil && s.followers[viewer.Id][id]
+
+	return p
+}
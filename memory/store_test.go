@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dzeban/conduit/app"
+	"github.com/dzeban/conduit/storetest"
+)
+
+func TestConcurrentFavorites(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+
+	author := &app.User{Name: "alice", Email: "alice@example.com"}
+	if err := s.AddUser(ctx, author); err != nil {
+		t.Fatal(err)
+	}
+
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	article := &app.Article{
+		Slug:    "title",
+		Title:   "Title",
+		Body:    "Body",
+		Author:  *app.ProfileFromUser(author),
+		Created: created,
+		Updated: created,
+	}
+	if err := s.CreateArticle(ctx, article); err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 50
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			name := fmt.Sprintf("user%d", i)
+			u := &app.User{Name: name, Email: name + "@example.com"}
+			if err := s.AddUser(ctx, u); err != nil {
+				t.Error(err)
+				return
+			}
+
+			if err := s.FavoriteArticle(ctx, app.ProfileFromUser(u), article); err != nil {
+				t.Error(err)
+			}
+
+			_, _ = s.GetArticle(ctx, article.Slug, app.ProfileFromUser(u))
+		}(i)
+	}
+	wg.Wait()
+
+	a, err := s.GetArticle(ctx, article.Slug, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.FavoritesCount != n {
+		t.Errorf("invalid favorites count, expected %d, got %d", n, a.FavoritesCount)
+	}
+}
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Store {
+		return NewStore()
+	})
+}
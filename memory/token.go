@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/dzeban/conduit/app"
+)
+
+// AddRefreshToken stores refresh token hash
+func (s *Store) AddRefreshToken(ctx context.Context, t *app.RefreshToken) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	s.refreshTokens[t.Hash] = *t
+	return nil
+}
+
+// DeleteRefreshToken deletes refresh token by hash and returns it. It returns
+// nil if token doesn't exist.
+func (s *Store) DeleteRefreshToken(ctx context.Context, hash string) (*app.RefreshToken, error) {
+	if err := s.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	t, ok := s.refreshTokens[hash]
+	if !ok {
+		return nil, nil
+	}
+
+	delete(s.refreshTokens, hash)
+	return &t, nil
+}
+
+// DeleteUserRefreshToken deletes refresh token by hash only if it's issued to
+// the user with id and returns it. It returns nil if there is no such token.
+func (s *Store) DeleteUserRefreshToken(ctx context.Context, userId int, hash string) (*app.RefreshToken, error) {
+	if err := s.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	t, ok := s.refreshTokens[hash]
+	if !ok || t.UserId != userId {
+		return nil, nil
+	}
+
+	delete(s.refreshTokens, hash)
+	return &t, nil
+}
+
+// RevokeToken adds token id to the revoked tokens. Revoked tokens that are
+// already expired are deleted because they are rejected anyway.
+func (s *Store) RevokeToken(ctx context.Context, id string, expires time.Time) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for revoked, exp := range s.revoked {
+		if exp.Before(now) {
+			delete(s.revoked, revoked)
+		}
+	}
+
+	if _, ok := s.revoked[id]; !ok {
+		s.revoked[id] = expires
+	}
+
+	return nil
+}
+
+// IsTokenRevoked checks if token id is revoked
+func (s *Store) IsTokenRevoked(ctx context.Context, id string) (bool, error) {
+	if err := s.rlock(ctx); err != nil {
+		return false, err
+	}
+	defer s.mu.RUnlock()
+
+	_, ok := s.revoked[id]
+	return ok, nil
+}
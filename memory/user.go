@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+)
+
+var (
+	errorUserEmailExists = errors.New("user with this email exists")
+	errorUserNameExists  = errors.New("user with this name exists")
+)
+
+// GetUser returns user by email or nil if there is no such user
+func (s *Store) GetUser(ctx context.Context, email string) (*app.User, error) {
+	if err := s.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	id, ok := s.userByEmail[email]
+	if !ok {
+		return nil, nil
+	}
+
+	u := s.users[id]
+	return &u, nil
+}
+
+// GetUserById returns user by id or nil if there is no such user
+func (s *Store) GetUserById(ctx context.Context, id int) (*app.User, error) {
+	if err := s.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &u, nil
+}
+
+// AddUser adds new user and sets its id
+func (s *Store) AddUser(ctx context.Context, user *app.User) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if _, ok := s.userByEmail[user.Email]; ok {
+		return errorUserEmailExists
+	}
+
+	if _, ok := s.userByName[user.Name]; ok {
+		return errorUserNameExists
+	}
+
+	s.lastUserId++
+	user.Id = s.lastUserId
+
+	u := *user
+	u.Token = ""
+	u.RefreshToken = ""
+
+	s.users[u.Id] = u
+	s.userByEmail[u.Email] = u.Id
+	s.userByName[u.Name] = u.Id
+
+	return nil
+}
+
+// UpdateUser modifies non-empty fields of the user found by id. Like SQL
+// update it does nothing when there is no such user.
+func (s *Store) UpdateUser(ctx context.Context, user *app.User) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	u, ok := s.users[user.Id]
+	if !ok {
+		return nil
+	}
+
+	newEmail := user.Email != "" && user.Email != u.Email
+	newName := user.Name != "" && user.Name != u.Name
+
+	// Check all of the conflicts before touching the indexes, so failed
+	// update leaves the user intact like a rolled back transaction
+	if _, ok := s.userByEmail[user.Email]; newEmail && ok {
+		return errorUserEmailExists
+	}
+
+	if _, ok := s.userByName[user.Name]; newName && ok {
+		return errorUserNameExists
+	}
+
+	if newEmail {
+		delete(s.userByEmail, u.Email)
+		u.Email = user.Email
+		s.userByEmail[u.Email] = u.Id
+	}
+
+	if newName {
+		delete(s.userByName, u.Name)
+		u.Name = user.Name
+		s.userByName[u.Name] = u.Id
+	}
+
+	if user.Bio != "" {
+		u.Bio = user.Bio
+	}
+
+	if user.Image != "" {
+		u.Image = user.Image
+	}
+
+	if user.PasswordHash != "" {
+		u.PasswordHash = user.PasswordHash
+	}
+
+	s.users[u.Id] = u
+
+	return nil
+}
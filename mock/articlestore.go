@@ -1,7 +1,9 @@
 package mock
 
 import (
+	"context"
 	"errors"
+	"sort"
 	"time"
 
 	"github.com/dzeban/conduit/app"
@@ -10,16 +12,19 @@ import (
 var (
 	ArticleValid = app.Article{
 		Id:          1,
+		Slug:        "title",
 		Title:       "Title",
 		Description: "Description",
 		Body:        "Body",
 		Author:      Author,
+		TagList:     []string{"go", "test"},
 		Created:     time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
 		Updated:     time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
 	}
 
 	ArticleUpdated = app.Article{
 		Id:          2,
+		Slug:        "other-title",
 		Title:       "Other title",
 		Description: "Other description",
 		Body:        "Other body",
@@ -35,45 +40,138 @@ var (
 	}
 )
 
-// ArticleStore is a fake implementation of article.Store as Go map
+// ArticleStore is a fake implementation of article.Store as Go map. It
+// doesn't filter, paginate or search articles, use memory store in tests that
+// need it.
 type ArticleStore struct {
 	ById   map[int]*app.Article
 	BySlug map[string]*app.Article
+
+	// Favorites maps article id to the set of user ids that favorited it
+	Favorites map[int]map[int]bool
 }
 
 func NewArticleStore() *ArticleStore {
 	as := &ArticleStore{
-		ById:   make(map[int]*app.Article),
-		BySlug: make(map[string]*app.Article),
+		ById:      make(map[int]*app.Article),
+		BySlug:    make(map[string]*app.Article),
+		Favorites: make(map[int]map[int]bool),
 	}
 
-	_ = as.CreateArticle(&ArticleValid)
-	_ = as.CreateArticle(&ArticleUpdated)
+	_ = as.CreateArticle(context.Background(), &ArticleValid)
+	_ = as.CreateArticle(context.Background(), &ArticleUpdated)
 
 	return as
 }
 
-func (as *ArticleStore) CreateArticle(a *app.Article) error {
+func (as *ArticleStore) CreateArticle(ctx context.Context, a *app.Article) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Generate id for new articles like the database does
+	if a.Id == 0 {
+		for id := range as.ById {
+			if id > a.Id {
+				a.Id = id
+			}
+		}
+		a.Id++
+	}
+
+	if a.Version == 0 {
+		a.Version = 1
+	}
+
 	as.ById[a.Id] = a
 	as.BySlug[a.Slug] = a
 	return nil
 }
 
-func (as *ArticleStore) ListArticles(f *app.ArticleListFilter) ([]*app.Article, error) {
-	panic("not implemented") // TODO: Implement
+// ListArticles returns all of the articles from the newest to the oldest.
+// Filter is ignored.
+func (as *ArticleStore) ListArticles(ctx context.Context, f *app.ArticleListFilter) ([]*app.Article, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var articles []*app.Article
+	for _, a := range as.ById {
+		articles = append(articles, as.forViewer(a, app.ProfileFromUser(f.CurrentUser)))
+	}
+
+	sort.Slice(articles, func(i, j int) bool {
+		return articles[i].Created.After(articles[j].Created)
+	})
+
+	return articles, nil
+}
+
+// CountArticles returns number of all articles. Filter is ignored.
+func (as *ArticleStore) CountArticles(ctx context.Context, f *app.ArticleListFilter) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return len(as.ById), nil
 }
 
-func (as *ArticleStore) GetArticle(slug string) (*app.Article, error) {
-	return as.BySlug[slug], nil
+// ListTags returns sorted list of tags used by articles in the store
+func (as *ArticleStore) ListTags(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	tags := []string{}
+	for _, a := range as.ById {
+		for _, tag := range a.TagList {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	sort.Strings(tags)
+
+	return tags, nil
+}
+
+func (as *ArticleStore) GetArticle(ctx context.Context, slug string, viewer *app.Profile) (*app.Article, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	a, ok := as.BySlug[slug]
+	if !ok {
+		return nil, nil
+	}
+
+	return as.forViewer(a, viewer), nil
 }
 
-func (as *ArticleStore) UpdateArticle(a *app.Article) error {
+func (as *ArticleStore) UpdateArticle(ctx context.Context, a *app.Article) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Reject update of the article modified after it was read
+	if stored, ok := as.ById[a.Id]; ok && stored.Version != a.Version {
+		return app.ErrorArticleVersionMismatch
+	}
+	a.Version++
+
 	as.ById[a.Id] = a
 	as.BySlug[a.Slug] = a
 	return nil
 }
 
-func (as *ArticleStore) DeleteArticle(id int) error {
+func (as *ArticleStore) DeleteArticle(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	a, ok := as.ById[id]
 	if !ok {
 		return errors.New("not found by id")
@@ -81,6 +179,43 @@ func (as *ArticleStore) DeleteArticle(id int) error {
 
 	delete(as.ById, id)
 	delete(as.BySlug, a.Slug)
+	delete(as.Favorites, id)
+
+	return nil
+}
+
+func (as *ArticleStore) FavoriteArticle(ctx context.Context, user *app.Profile, a *app.Article) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if _, ok := as.ById[a.Id]; !ok {
+		return errors.New("not found by id")
+	}
+
+	if as.Favorites[a.Id] == nil {
+		as.Favorites[a.Id] = make(map[int]bool)
+	}
+	as.Favorites[a.Id][user.Id] = true
 
 	return nil
 }
+
+func (as *ArticleStore) UnfavoriteArticle(ctx context.Context, user *app.Profile, a *app.Article) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	delete(as.Favorites[a.Id], user.Id)
+	return nil
+}
+
+// forViewer returns a copy of the article with favorites fields filled for the
+// viewer
+func (as *ArticleStore) forViewer(a *app.Article, viewer *app.Profile) *app.Article {
+	article := *a
+	article.FavoritesCount = len(as.Favorites[a.Id])
+	article.Favorited = viewer != nil && as.Favorites[a.Id][viewer.Id]
+
+	return &article
+}
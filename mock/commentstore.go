@@ -0,0 +1,104 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/dzeban/conduit/app"
+)
+
+var (
+	CommentValid = app.Comment{
+		Id:        1,
+		ArticleId: ArticleValid.Id,
+		Body:      "Comment",
+		Author:    Author,
+		Created:   time.Date(2020, 1, 3, 3, 4, 5, 0, time.UTC),
+		Updated:   time.Date(2020, 1, 3, 3, 4, 5, 0, time.UTC),
+	}
+)
+
+// CommentStore is a fake implementation of comment.Store as Go map
+type CommentStore struct {
+	ById map[int]app.Comment
+}
+
+func NewCommentStore() *CommentStore {
+	cs := &CommentStore{
+		ById: make(map[int]app.Comment),
+	}
+
+	comment := CommentValid
+	_ = cs.CreateComment(context.Background(), &comment)
+
+	return cs
+}
+
+func (cs *CommentStore) CreateComment(ctx context.Context, c *app.Comment) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Generate id for new comments like the database does
+	if c.Id == 0 {
+		for id := range cs.ById {
+			if id > c.Id {
+				c.Id = id
+			}
+		}
+		c.Id++
+	}
+
+	cs.ById[c.Id] = *c
+	return nil
+}
+
+func (cs *CommentStore) GetComment(ctx context.Context, id int) (*app.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c, ok := cs.ById[id]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+func (cs *CommentStore) ListComments(ctx context.Context, articleId int, viewer *app.Profile) ([]*app.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	comments := []*app.Comment{}
+	for _, c := range cs.ById {
+		if c.ArticleId != articleId {
+			continue
+		}
+
+		c := c
+		comments = append(comments, &c)
+	}
+
+	// Oldest comments go first
+	sort.Slice(comments, func(i, j int) bool {
+		return comments[i].Created.Before(comments[j].Created)
+	})
+
+	return comments, nil
+}
+
+func (cs *CommentStore) DeleteComment(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if _, ok := cs.ById[id]; !ok {
+		return errors.New("not found by id")
+	}
+
+	delete(cs.ById, id)
+	return nil
+}
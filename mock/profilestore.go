@@ -1,6 +1,10 @@
 package mock
 
-import "github.com/dzeban/conduit/app"
+import (
+	"context"
+
+	"github.com/dzeban/conduit/app"
+)
 
 var (
 	Profile1 = app.Profile{
@@ -18,11 +22,15 @@ var (
 // ProfilesStore is a fake implementation of profiles.Store as Go map
 type ProfilesStore struct {
 	m map[string]app.Profile
+
+	// Followers maps follower id to the set of followee ids
+	Followers map[int]map[int]bool
 }
 
 func NewProfilesStore() *ProfilesStore {
 	ps := &ProfilesStore{
-		m: make(map[string]app.Profile),
+		m:         make(map[string]app.Profile),
+		Followers: make(map[int]map[int]bool),
 	}
 
 	for _, profile := range []app.Profile{Profile1, Profile2} {
@@ -32,11 +40,44 @@ func NewProfilesStore() *ProfilesStore {
 	return ps
 }
 
-func (ps *ProfilesStore) GetProfile(name string, follower *app.Profile) (*app.Profile, error) {
+func (ps *ProfilesStore) GetProfile(ctx context.Context, name string, follower *app.Profile) (*app.Profile, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	p, ok := ps.m[name]
 	if !ok {
 		return nil, app.ErrorProfileNotFound
 	}
 
+	p.Following = follower != nil && ps.IsFollowing(follower.Id, p.Id)
+
 	return &p, nil
 }
+
+func (ps *ProfilesStore) FollowProfile(ctx context.Context, follower, followee *app.Profile) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if ps.Followers[follower.Id] == nil {
+		ps.Followers[follower.Id] = make(map[int]bool)
+	}
+	ps.Followers[follower.Id][followee.Id] = true
+
+	return nil
+}
+
+func (ps *ProfilesStore) UnfollowProfile(ctx context.Context, follower, followee *app.Profile) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	delete(ps.Followers[follower.Id], followee.Id)
+	return nil
+}
+
+// IsFollowing tells whether user with follower id follows user with followee id
+func (ps *ProfilesStore) IsFollowing(follower, followee int) bool {
+	return ps.Followers[follower][followee]
+}
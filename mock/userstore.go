@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"context"
 	"math/rand"
 
 	"github.com/dzeban/conduit/app"
@@ -40,16 +41,20 @@ var (
 type UserStore struct {
 	ById    map[int]app.User
 	ByEmail map[string]app.User
+
+	// RefreshTokens maps token hash to the refresh token
+	RefreshTokens map[string]app.RefreshToken
 }
 
 func NewUserStore() *UserStore {
 	us := &UserStore{
-		ById:    make(map[int]app.User),
-		ByEmail: make(map[string]app.User),
+		ById:          make(map[int]app.User),
+		ByEmail:       make(map[string]app.User),
+		RefreshTokens: make(map[string]app.RefreshToken),
 	}
 
 	for _, user := range []*app.User{&UserValid, &UserUpdatedUsername, &UserInvalid} {
-		err := us.AddUser(user)
+		err := us.AddUser(context.Background(), user)
 		if err != nil {
 			panic(err)
 		}
@@ -58,7 +63,11 @@ func NewUserStore() *UserStore {
 	return us
 }
 
-func (us *UserStore) GetUser(email string) (*app.User, error) {
+func (us *UserStore) GetUser(ctx context.Context, email string) (*app.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	u, ok := us.ByEmail[email]
 	if !ok {
 		return nil, nil
@@ -66,7 +75,11 @@ func (us *UserStore) GetUser(email string) (*app.User, error) {
 	return &u, nil
 }
 
-func (us *UserStore) GetUserById(id int) (*app.User, error) {
+func (us *UserStore) GetUserById(ctx context.Context, id int) (*app.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	u, ok := us.ById[id]
 	if !ok {
 		return nil, nil
@@ -74,7 +87,11 @@ func (us *UserStore) GetUserById(id int) (*app.User, error) {
 	return &u, nil
 }
 
-func (us *UserStore) AddUser(user *app.User) error {
+func (us *UserStore) AddUser(ctx context.Context, user *app.User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	if user.Id == 0 {
 		user.Id = 10 + rand.Int() // "10 + " is needed to avoid overlap with predefined mock users
 	}
@@ -84,7 +101,11 @@ func (us *UserStore) AddUser(user *app.User) error {
 	return nil
 }
 
-func (us *UserStore) UpdateUser(newUser *app.User) error {
+func (us *UserStore) UpdateUser(ctx context.Context, newUser *app.User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	user, ok := us.ById[newUser.Id]
 	if !ok {
 		panic("user not found")
@@ -117,3 +138,40 @@ func (us *UserStore) UpdateUser(newUser *app.User) error {
 
 	return nil
 }
+
+func (us *UserStore) AddRefreshToken(ctx context.Context, t *app.RefreshToken) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	us.RefreshTokens[t.Hash] = *t
+	return nil
+}
+
+func (us *UserStore) DeleteRefreshToken(ctx context.Context, hash string) (*app.RefreshToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	t, ok := us.RefreshTokens[hash]
+	if !ok {
+		return nil, nil
+	}
+
+	delete(us.RefreshTokens, hash)
+	return &t, nil
+}
+
+func (us *UserStore) DeleteUserRefreshToken(ctx context.Context, userId int, hash string) (*app.RefreshToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	t, ok := us.RefreshTokens[hash]
+	if !ok || t.UserId != userId {
+		return nil, nil
+	}
+
+	delete(us.RefreshTokens, hash)
+	return &t, nil
+}
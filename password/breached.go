@@ -0,0 +1,91 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// rangePrefixLen is the length of hash prefix used as a range key, the same as
+// in the Have I Been Pwned range API
+const rangePrefixLen = 5
+
+// BreachedList is an offline list of breached passwords. Passwords are stored
+// as SHA-1 hashes grouped in ranges by hash prefix, k-anonymity style, so the
+// lookup only touches the range of the password hash.
+type BreachedList struct {
+	ranges map[string]map[string]struct{}
+}
+
+// LoadBreachedList reads breached list from file, see ReadBreachedList for
+// the format
+func LoadBreachedList(path string) (*BreachedList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open breached passwords list")
+	}
+	defer f.Close()
+
+	return ReadBreachedList(f)
+}
+
+// ReadBreachedList reads breached list with one uppercase or lowercase SHA-1
+// hex hash per line. Hashes may be followed by ":<count>" like in the Have I
+// Been Pwned dumps. Empty lines and lines starting with # are skipped.
+func ReadBreachedList(r io.Reader) (*BreachedList, error) {
+	l := &BreachedList{ranges: make(map[string]map[string]struct{})}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			line = line[:i]
+		}
+
+		if len(line) != sha1.Size*2 {
+			return nil, errors.Errorf("invalid breached password hash on line %d", n)
+		}
+
+		if _, err := hex.DecodeString(line); err != nil {
+			return nil, errors.Errorf("invalid breached password hash on line %d", n)
+		}
+
+		l.add(strings.ToUpper(line))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read breached passwords list")
+	}
+
+	return l, nil
+}
+
+func (l *BreachedList) add(hash string) {
+	prefix, suffix := hash[:rangePrefixLen], hash[rangePrefixLen:]
+
+	r, ok := l.ranges[prefix]
+	if !ok {
+		r = make(map[string]struct{})
+		l.ranges[prefix] = r
+	}
+
+	r[suffix] = struct{}{}
+}
+
+// Contains reports if password is in the list
+func (l *BreachedList) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	_, ok := l.ranges[hash[:rangePrefixLen]][hash[rangePrefixLen:]]
+	return ok
+}
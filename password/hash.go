@@ -6,7 +6,14 @@
 // against encoded string created with `HashAndEncode`.
 //
 // This packages uses Argon2 for password hashing with unique per-password salt
-// and hash params that are described in package constants.
+// and hash params that are described in package constants. Defaults may be
+// changed with SetDefaults, hashes created with weaker params are detected by
+// NeedsRehash.
+//
+// Hashes of other formats, e.g. imported from legacy systems, can be checked
+// but not created. Their hashers are registered with RegisterHasher, bcrypt
+// and scrypt are supported out of the box. NeedsRehash reports them, so they
+// are replaced with Argon2 hashes.
 package password
 
 import (
@@ -26,6 +33,7 @@ func (pe passwordError) Error() string { return string(pe) }
 
 const (
 	encodingHashType = "argon2id"
+	argon2Prefix     = "$" + encodingHashType + "$"
 
 	// Default parameters for hashing
 	DefaultIterations = 5
@@ -41,6 +49,37 @@ const (
 	ErrHash        = passwordError("invalid hash")
 )
 
+// defaults are hash params used for new hashes. Salt is not used.
+var defaults = HashParams{
+	Iterations: DefaultIterations,
+	Memory:     DefaultMemory,
+	Threads:    DefaultThreads,
+	Len:        DefaultLen,
+}
+
+// SetDefaults changes params used for new hashes. Salt is ignored. It's meant
+// to be called once on startup and is not safe for concurrent use with the
+// hashing functions.
+func SetDefaults(params HashParams) error {
+	if params.Iterations == 0 || params.Memory == 0 || params.Threads == 0 || params.Len == 0 {
+		return ErrHashParams
+	}
+
+	defaults = HashParams{
+		Iterations: params.Iterations,
+		Memory:     params.Memory,
+		Threads:    params.Threads,
+		Len:        params.Len,
+	}
+
+	return nil
+}
+
+// Defaults returns params used for new hashes without salt
+func Defaults() HashParams {
+	return defaults
+}
+
 // genSalt generates random salt of n bytes size
 func genSalt(n int) ([]byte, error) {
 	b := make([]byte, n)
@@ -69,13 +108,18 @@ func NewHashParams() (HashParams, error) {
 		return HashParams{}, err
 	}
 
-	return HashParams{
-		Salt:       salt,
-		Iterations: DefaultIterations,
-		Memory:     DefaultMemory,
-		Threads:    DefaultThreads,
-		Len:        DefaultLen,
-	}, nil
+	params := defaults
+	params.Salt = salt
+
+	return params, nil
+}
+
+// Weaker reports if any of the params is weaker than the other params
+func (p HashParams) Weaker(other HashParams) bool {
+	return p.Iterations < other.Iterations ||
+		p.Memory < other.Memory ||
+		p.Threads < other.Threads ||
+		p.Len < other.Len
 }
 
 // Encode converts hash from byte slice to the string with hash params
@@ -177,8 +221,9 @@ func HashAndEncode(password string) (string, error) {
 	return Encode(hash, params), nil
 }
 
-// Check takes plaintext password and compares it to the encoded hash value
-func Check(password string, encoded string) (bool, error) {
+// checkArgon2 takes plaintext password and compares it to the encoded Argon2
+// hash value
+func checkArgon2(password string, encoded string) (bool, error) {
 	decodedHash, decodedParams, err := Decode(encoded)
 	if err != nil {
 		return false, errors.Wrap(err, "failed to decode password hash for compare")
@@ -192,3 +237,23 @@ func Check(password string, encoded string) (bool, error) {
 
 	return false, nil
 }
+
+// NeedsRehash reports if the encoded hash is not Argon2 hash or was created
+// with params weaker than the current defaults, so it should be replaced with
+// a new hash when the plaintext password is known, i.e. on login.
+func NeedsRehash(encoded string) (bool, error) {
+	if !strings.HasPrefix(encoded, argon2Prefix) {
+		if _, ok := hasherFor(encoded); !ok {
+			return false, ErrHashType
+		}
+
+		return true, nil
+	}
+
+	_, params, err := Decode(encoded)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to decode password hash for rehash check")
+	}
+
+	return params.Weaker(defaults), nil
+}
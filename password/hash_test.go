@@ -91,6 +91,76 @@ func TestCheck(t *testing.T) {
 			false,
 			false,
 		},
+		{
+			"BcryptEquals",
+			"test",
+			"$2a$04$7hwY4UqqKN8jf.hYwWp8be8NCVTiwRViNKf9OCQb.UlkWNXOHpuwO",
+			true,
+			false,
+		},
+		{
+			"BcryptDiffers",
+			"test1",
+			"$2a$04$7hwY4UqqKN8jf.hYwWp8be8NCVTiwRViNKf9OCQb.UlkWNXOHpuwO",
+			false,
+			false,
+		},
+		{
+			"ScryptEquals",
+			"test",
+			"$scrypt$ln=10,r=8,p=1$c2FsdHNhbHRzYWx0c2FsdA$0xy1FMA4tPUZaKCdRcLgibM+zVFZWaMzTYiHwnAKogs",
+			true,
+			false,
+		},
+		{
+			"ScryptDiffers",
+			"test1",
+			"$scrypt$ln=10,r=8,p=1$c2FsdHNhbHRzYWx0c2FsdA$0xy1FMA4tPUZaKCdRcLgibM+zVFZWaMzTYiHwnAKogs",
+			false,
+			false,
+		},
+		{
+			"ScryptInvalidParams",
+			"test",
+			"$scrypt$ln=x$c2FsdHNhbHRzYWx0c2FsdA$0xy1FMA4tPUZaKCdRcLgibM+zVFZWaMzTYiHwnAKogs",
+			false,
+			true,
+		},
+		{
+			"ScryptEmptyHash",
+			"anything",
+			"$scrypt$ln=10,r=8,p=1$c2FsdHNhbHRzYWx0c2FsdA$",
+			false,
+			true,
+		},
+		{
+			"ScryptShortHash",
+			"test",
+			"$scrypt$ln=10,r=8,p=1$c2FsdHNhbHRzYWx0c2FsdA$0xy1FMA4tPU",
+			false,
+			true,
+		},
+		{
+			"ScryptHugeR",
+			"test",
+			"$scrypt$ln=1,r=1073741823,p=1$c2FsdHNhbHRzYWx0c2FsdA$0xy1FMA4tPUZaKCdRcLgibM+zVFZWaMzTYiHwnAKogs",
+			false,
+			true,
+		},
+		{
+			"ScryptHugeP",
+			"test",
+			"$scrypt$ln=1,r=1,p=1073741823$c2FsdHNhbHRzYWx0c2FsdA$0xy1FMA4tPUZaKCdRcLgibM+zVFZWaMzTYiHwnAKogs",
+			false,
+			true,
+		},
+		{
+			"UnknownType",
+			"test",
+			"$md5$xxx",
+			false,
+			true,
+		},
 	}
 
 	for _, test := range tests {
@@ -161,3 +231,71 @@ func TestDecode(t *testing.T) {
 		}
 	}
 }
+
+func TestNeedsRehash(t *testing.T) {
+	defer SetDefaults(Defaults())
+
+	err := SetDefaults(HashParams{
+		Iterations: DefaultIterations,
+		Memory:     64 * 1024,
+		Threads:    DefaultThreads,
+		Len:        DefaultLen,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		hash   string
+		rehash bool
+	}{
+		{
+			"WeakerMemory",
+			"$argon2id$v=19$m=32768,t=5,p=1$62jx8XXcWFckh+9QjSkefA$Rav8qgyBopeMfDaBbk5jHs+UGUBPqcRqfxNNlR9RqBlV6ZrC/t8/05BYCwT6KKEbI5H4lOuqSLkyxClXm1WA4w",
+			true,
+		},
+		{
+			"Current",
+			"$argon2id$v=19$m=65536,t=5,p=1$62jx8XXcWFckh+9QjSkefA$Rav8qgyBopeMfDaBbk5jHs+UGUBPqcRqfxNNlR9RqBlV6ZrC/t8/05BYCwT6KKEbI5H4lOuqSLkyxClXm1WA4w",
+			false,
+		},
+		{
+			"Stronger",
+			"$argon2id$v=19$m=131072,t=10,p=4$62jx8XXcWFckh+9QjSkefA$Rav8qgyBopeMfDaBbk5jHs+UGUBPqcRqfxNNlR9RqBlV6ZrC/t8/05BYCwT6KKEbI5H4lOuqSLkyxClXm1WA4w",
+			false,
+		},
+		{
+			"Bcrypt",
+			"$2a$04$7hwY4UqqKN8jf.hYwWp8be8NCVTiwRViNKf9OCQb.UlkWNXOHpuwO",
+			true,
+		},
+		{
+			"Scrypt",
+			"$scrypt$ln=10,r=8,p=1$c2FsdHNhbHRzYWx0c2FsdA$0xy1FMA4tPUZaKCdRcLgibM+zVFZWaMzTYiHwnAKogs",
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		rehash, err := NeedsRehash(test.hash)
+		if err != nil {
+			t.Fatalf("%s test failed: unexpected error %v", test.name, err)
+		}
+
+		if rehash != test.rehash {
+			t.Fatalf("%s test failed: unexpected result, want %v, got %v", test.name, test.rehash, rehash)
+		}
+	}
+}
+
+func TestRegisterHasher(t *testing.T) {
+	RegisterHasher("$plain$", HasherFunc(func(password, encoded string) (bool, error) {
+		return encoded == "$plain$"+password, nil
+	}))
+
+	ok, err := Check("test", "$plain$test")
+	if err != nil || !ok {
+		t.Fatalf("registered hasher is not used: %v, error %v", ok, err)
+	}
+}
@@ -0,0 +1,72 @@
+package password
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+const scryptPrefix = "$scrypt$"
+
+// Limits of scrypt params. Cost of the check grows linearly with r and p, so
+// they are bounded like ln to not let a crafted hash exhaust CPU and memory.
+// Hash shorter than scryptMinHashLen is rejected because an empty one matches
+// any password.
+const (
+	scryptMaxR       = 32
+	scryptMaxP       = 16
+	scryptMinHashLen = 16
+)
+
+// checkBcrypt compares password to bcrypt hash ($2a$, $2b$ or $2y$)
+func checkBcrypt(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "failed to check bcrypt hash")
+	}
+
+	return true, nil
+}
+
+// checkScrypt compares password to scrypt hash in PHC string format
+// $scrypt$ln=<log2(N)>,r=<r>,p=<p>$<salt>$<hash> where salt and hash are
+// base64 encoded without padding
+func checkScrypt(password, encoded string) (bool, error) {
+	vals := strings.Split(encoded, "$")
+
+	if len(vals) != 5 {
+		return false, ErrHashInvalid
+	}
+
+	var ln uint
+	var r, p int
+	_, err := fmt.Sscanf(vals[2], "ln=%d,r=%d,p=%d", &ln, &r, &p)
+	if err != nil || ln == 0 || ln > 30 || r < 1 || r > scryptMaxR || p < 1 || p > scryptMaxP {
+		return false, ErrHashParams
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(vals[3])
+	if err != nil {
+		return false, ErrHashSalt
+	}
+
+	decodedHash, err := base64.RawStdEncoding.DecodeString(vals[4])
+	if err != nil || len(decodedHash) < scryptMinHashLen {
+		return false, ErrHash
+	}
+
+	hash, err := scrypt.Key([]byte(password), salt, 1<<ln, r, p, len(decodedHash))
+	if err != nil {
+		return false, ErrHashParams
+	}
+
+	return subtle.ConstantTimeCompare(hash, decodedHash) == 1, nil
+}
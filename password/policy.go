@@ -0,0 +1,143 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// Default password policy params
+	DefaultMinLength  = 8
+	DefaultMaxLength  = 128
+	DefaultMinClasses = 1
+
+	// minIdentityLen is the shortest username or email part that is checked
+	// in password. Shorter ones would reject too many good passwords.
+	minIdentityLen = 3
+
+	ErrPolicy = passwordError("invalid password policy")
+)
+
+// Policy describes requirements for new passwords. Existing passwords are not
+// checked against it, so it can be changed at any time.
+type Policy struct {
+	// MinLength and MaxLength bound password length in characters. Max length
+	// bounds the cost of hashing.
+	MinLength int
+	MaxLength int
+
+	// MinClasses is the number of character classes required in password.
+	// Classes are lowercase letters, uppercase letters, digits and others.
+	MinClasses int
+
+	// DisallowIdentity rejects passwords containing username or email
+	DisallowIdentity bool
+
+	// Breached is the list of breached passwords that are rejected. It's not
+	// checked when nil.
+	Breached *BreachedList
+}
+
+// policy is used to validate new passwords
+var policy = Policy{
+	MinLength:        DefaultMinLength,
+	MaxLength:        DefaultMaxLength,
+	MinClasses:       DefaultMinClasses,
+	DisallowIdentity: true,
+}
+
+// SetPolicy changes policy for new passwords. Like SetDefaults it's meant to
+// be called once on startup and is not safe for concurrent use with Validate.
+func SetPolicy(p Policy) error {
+	if p.MinLength < 1 || p.MaxLength < p.MinLength || p.MinClasses < 0 || p.MinClasses > 4 {
+		return ErrPolicy
+	}
+
+	policy = p
+	return nil
+}
+
+// CurrentPolicy returns policy for new passwords
+func CurrentPolicy() Policy {
+	return policy
+}
+
+// Validate checks new password against the current policy. Identities are
+// username, email and the like that must not be used in password. It returns
+// the list of problems suitable for field errors or nil if password is fine.
+func Validate(password string, identities ...string) []string {
+	return policy.Check(password, identities...)
+}
+
+// Check returns the list of problems of password according to the policy
+func (p Policy) Check(password string, identities ...string) []string {
+	var problems []string
+
+	n := utf8.RuneCountInString(password)
+	if n < p.MinLength {
+		problems = append(problems, fmt.Sprintf("is too short, minimum is %d characters", p.MinLength))
+	}
+
+	if n > p.MaxLength {
+		problems = append(problems, fmt.Sprintf("is too long, maximum is %d characters", p.MaxLength))
+	}
+
+	if classes(password) < p.MinClasses {
+		problems = append(problems, fmt.Sprintf("must contain at least %d of lowercase letters, uppercase letters, digits and symbols", p.MinClasses))
+	}
+
+	if p.DisallowIdentity && containsIdentity(password, identities) {
+		problems = append(problems, "must not contain username or email")
+	}
+
+	if p.Breached != nil && p.Breached.Contains(password) {
+		problems = append(problems, "is known to be breached, choose another one")
+	}
+
+	return problems
+}
+
+// classes returns the number of character classes used in s
+func classes(s string) int {
+	var lower, upper, digit, other int
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+
+	return lower + upper + digit + other
+}
+
+// containsIdentity reports if password contains any of identities ignoring
+// case. Local part of emails is checked separately because it's often the
+// username.
+func containsIdentity(password string, identities []string) bool {
+	password = strings.ToLower(password)
+
+	for _, id := range identities {
+		id = strings.ToLower(id)
+
+		parts := []string{id}
+		if i := strings.LastIndex(id, "@"); i >= 0 {
+			parts = append(parts, id[:i])
+		}
+
+		for _, part := range parts {
+			if len(part) >= minIdentityLen && strings.Contains(password, part) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
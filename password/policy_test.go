@@ -0,0 +1,104 @@
+package password
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	breached, err := ReadBreachedList(strings.NewReader(`# breached passwords
+E38AD214943DAAD1D64C102FAEC29DE4AFE9DA3D:2413945
+
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := Policy{
+		MinLength:        8,
+		MaxLength:        16,
+		MinClasses:       2,
+		DisallowIdentity: true,
+		Breached:         breached,
+	}
+
+	tests := []struct {
+		name       string
+		password   string
+		identities []string
+		problems   []string
+	}{
+		{
+			"Valid",
+			"horse battery",
+			[]string{"test", "test@example.com"},
+			nil,
+		},
+		{
+			"TooShort",
+			"a1b2",
+			nil,
+			[]string{"is too short, minimum is 8 characters"},
+		},
+		{
+			"TooLong",
+			"correct horse battery staple",
+			nil,
+			[]string{"is too long, maximum is 16 characters"},
+		},
+		{
+			"Classes",
+			"abcdefghij",
+			nil,
+			[]string{"must contain at least 2 of lowercase letters, uppercase letters, digits and symbols"},
+		},
+		{
+			"Username",
+			"my-Admin-pass",
+			[]string{"admin", "someone@example.com"},
+			[]string{"must not contain username or email"},
+		},
+		{
+			"EmailLocalPart",
+			"JohnDoe1234",
+			[]string{"jd", "johndoe@example.com"},
+			[]string{"must not contain username or email"},
+		},
+		{
+			"ShortIdentity",
+			"jd-secret-1",
+			[]string{"jd"},
+			nil,
+		},
+		{
+			"Breached",
+			"password1",
+			nil,
+			[]string{"is known to be breached, choose another one"},
+		},
+	}
+
+	for _, test := range tests {
+		problems := p.Check(test.password, test.identities...)
+		if !reflect.DeepEqual(problems, test.problems) {
+			t.Errorf("%s test failed: want %q, got %q", test.name, test.problems, problems)
+		}
+	}
+}
+
+func TestReadBreachedListInvalid(t *testing.T) {
+	_, err := ReadBreachedList(strings.NewReader("password1\n"))
+	if err == nil {
+		t.Fatal("expected error for invalid hash")
+	}
+}
+
+func TestSetPolicy(t *testing.T) {
+	defer SetPolicy(CurrentPolicy())
+
+	err := SetPolicy(Policy{MinLength: 10, MaxLength: 5})
+	if err != ErrPolicy {
+		t.Errorf("expected %v, got %v", ErrPolicy, err)
+	}
+}
@@ -0,0 +1,69 @@
+package password
+
+import (
+	"strings"
+	"sync"
+)
+
+// Hasher checks plaintext passwords against hashes of a particular format
+type Hasher interface {
+	Check(password, encoded string) (bool, error)
+}
+
+// HasherFunc is an adapter to use ordinary function as Hasher
+type HasherFunc func(password, encoded string) (bool, error)
+
+func (f HasherFunc) Check(password, encoded string) (bool, error) {
+	return f(password, encoded)
+}
+
+var (
+	hashersMu sync.RWMutex
+
+	// hashers are keyed by the hash prefix in PHC string format, e.g.
+	// "$argon2id$" or "$2a$"
+	hashers = map[string]Hasher{
+		argon2Prefix: HasherFunc(checkArgon2),
+		"$2a$":       HasherFunc(checkBcrypt),
+		"$2b$":       HasherFunc(checkBcrypt),
+		"$2y$":       HasherFunc(checkBcrypt),
+		scryptPrefix: HasherFunc(checkScrypt),
+	}
+)
+
+// RegisterHasher makes hasher available to check hashes with the prefix.
+// Hasher registered with the existing prefix replaces the previous one.
+func RegisterHasher(prefix string, h Hasher) {
+	hashersMu.Lock()
+	defer hashersMu.Unlock()
+
+	hashers[prefix] = h
+}
+
+// hasherFor returns hasher with the longest prefix matching encoded hash
+func hasherFor(encoded string) (Hasher, bool) {
+	hashersMu.RLock()
+	defer hashersMu.RUnlock()
+
+	var hasher Hasher
+	var matched string
+	for prefix, h := range hashers {
+		if strings.HasPrefix(encoded, prefix) && len(prefix) > len(matched) {
+			hasher = h
+			matched = prefix
+		}
+	}
+
+	return hasher, hasher != nil
+}
+
+// Check takes plaintext password and compares it to the encoded hash value.
+// Hash format is detected by its prefix.
+func Check(password string, encoded string) (bool, error) {
+	h, ok := hasherFor(encoded)
+	if !ok {
+		return false, ErrHashType
+	}
+
+	return h.Check(password, encoded)
+}
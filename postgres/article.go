@@ -1,10 +1,13 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 
 	"github.com/dzeban/conduit/app"
@@ -21,14 +24,51 @@ type PostgresArticle struct {
 	Body        sql.NullString
 	Created     time.Time
 	Updated     time.Time
+	Version     int
 	AuthorId    int
 	AuthorName  string
-	AuthorBio   string
+	AuthorBio   sql.NullString
 	AuthorImage sql.NullString
 	Following   bool
+	TagList     pq.StringArray
+
+	Favorited      bool
+	FavoritesCount int
+
+	Snippet sql.NullString
+}
+
+// tagListColumn is a subquery column that aggregates article tags into array.
+// It expects articles table to be aliased as "a".
+const tagListColumn = `
+	ARRAY(
+		SELECT t.name
+		FROM article_tags at
+		JOIN tags t ON (at.tag_id = t.id)
+		WHERE at.article_id = a.id
+		ORDER BY t.name
+	) as tag_list
+`
+
+// favoritesCountColumn is a subquery column that counts how many users
+// favorited the article. It expects articles table to be aliased as "a".
+const favoritesCountColumn = `
+	(SELECT count(*) FROM favorites fav WHERE fav.article_id = a.id) as favorites_count
+`
+
+// favoritedColumn returns a subquery column that tells whether the article is
+// favorited by the user with a given id. It expects articles table to be
+// aliased as "a".
+func favoritedColumn(userId int) sq.Sqlizer {
+	return sq.Expr(`
+		EXISTS (
+			SELECT 1 FROM favorites fav
+			WHERE fav.article_id = a.id AND fav.user_id = ?
+		) as favorited
+	`, userId)
 }
 
-func (s Store) ListArticles(f *app.ArticleListFilter) ([]*app.Article, error) {
+func (s Store) ListArticles(ctx context.Context, f *app.ArticleListFilter) ([]*app.Article, error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	q := psql.Select(`
 				a.id as id,
@@ -38,25 +78,53 @@ func (s Store) ListArticles(f *app.ArticleListFilter) ([]*app.Article, error) {
 				a.body as body,
 				a.created as created,
 				a.updated as updated,
+				a.version as version,
 				a.author_id as author_id,
 				u.name as author_name,
 				u.bio as author_bio,
 				u.image as author_image
 			`).
+		Column(tagListColumn).
+		Column(favoritesCountColumn).
 		From("articles a").
-		Join("users u on (a.author_id = u.id)").
-		OrderBy("created DESC")
+		Join("users u on (a.author_id = u.id)")
 
 	if f.CurrentUser != nil {
-		q = q.LeftJoin("followers f on (u.id = f.followee)").
-			Where("f.follower = ?", f.CurrentUser.Id).
-			Columns("f.followee != 0 as following")
+		q = q.LeftJoin("followers f on (u.id = f.followee AND f.follower = ?)", f.CurrentUser.Id).
+			Columns("f.followee IS NOT NULL as following").
+			Column(favoritedColumn(f.CurrentUser.Id))
 	}
 
-	if f.Author != nil {
-		q = q.Where("author_id = ?", f.Author.Id)
+	q = whereArticleFilter(q, f)
+
+	// Search results are ordered by relevance and have highlighted snippets.
+	// Body is escaped before ts_headline the same way as html.EscapeString
+	// does, so the only markup in the snippet is the highlighting.
+	if f.Query != "" {
+		q = q.
+			Column(sq.Expr(`
+				ts_headline(
+					'english',
+					replace(replace(replace(replace(replace(
+						coalesce(a.body, ''),
+						'&', '&amp;'),
+						'<', '&lt;'),
+						'>', '&gt;'),
+						'"', '&#34;'),
+						'''', '&#39;'),
+					websearch_to_tsquery('english', ?),
+					'StartSel=<b>, StopSel=</b>, MaxFragments=2'
+				) as snippet
+			`, f.Query)).
+			OrderByClause("ts_rank(a.search, websearch_to_tsquery('english', ?)) DESC", f.Query)
+	}
+	q = q.OrderBy("a.created DESC", "a.id DESC")
+
+	// Keyset pagination. It's applied here and not in whereArticleFilter
+	// because it must not affect the total count of articles.
+	if f.After != nil {
+		q = q.Where("(a.created, a.id) < (?, ?)", f.After.Created, f.After.Id)
 	}
-	// q = q.Where("favorite = ?", f.Favorite)
 
 	q = q.Limit(f.Limit).Offset(f.Offset)
 
@@ -65,7 +133,7 @@ func (s Store) ListArticles(f *app.ArticleListFilter) ([]*app.Article, error) {
 		return nil, errors.Wrap(err, "failed to build select query")
 	}
 
-	rows, err := s.db.Queryx(query, args...)
+	rows, err := s.db.QueryxContext(ctx, query, args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to query articles")
 	}
@@ -99,11 +167,18 @@ func (s Store) ListArticles(f *app.ArticleListFilter) ([]*app.Article, error) {
 			Body:        a.Body.String,
 			Created:     a.Created,
 			Updated:     a.Updated,
+			Version:     a.Version,
 			Author: app.Profile{
-				Id:    a.AuthorId,
-				Name:  a.AuthorName,
-				Image: a.AuthorImage.String,
+				Id:        a.AuthorId,
+				Name:      a.AuthorName,
+				Bio:       a.AuthorBio.String,
+				Image:     a.AuthorImage.String,
+				Following: a.Following,
 			},
+			TagList:        []string(a.TagList),
+			Favorited:      a.Favorited,
+			FavoritesCount: a.FavoritesCount,
+			Snippet:        a.Snippet.String,
 		})
 	}
 
@@ -114,43 +189,118 @@ func (s Store) ListArticles(f *app.ArticleListFilter) ([]*app.Article, error) {
 	return articles, nil
 }
 
-// Get returns a single article by its slug
-func (s Store) GetArticle(slug string) (*app.Article, error) {
+// whereArticleFilter adds conditions from the filter to the articles query.
+// Limit and offset are not applied here. It expects articles table to be
+// aliased as "a".
+func whereArticleFilter(q sq.SelectBuilder, f *app.ArticleListFilter) sq.SelectBuilder {
+	if f.FeedOf != nil {
+		q = q.Where(`EXISTS (
+			SELECT 1 FROM followers ff
+			WHERE ff.followee = a.author_id AND ff.follower = ?
+		)`, f.FeedOf.Id)
+	}
+
+	if f.Author != nil {
+		q = q.Where("a.author_id = ?", f.Author.Id)
+	}
+
+	if f.Tag != "" {
+		q = q.Where(`EXISTS (
+			SELECT 1
+			FROM article_tags at
+			JOIN tags t ON (at.tag_id = t.id)
+			WHERE at.article_id = a.id AND t.name = ?
+		)`, f.Tag)
+	}
+
+	if f.Favorited != nil {
+		q = q.Where(`EXISTS (
+			SELECT 1 FROM favorites fav
+			WHERE fav.article_id = a.id AND fav.user_id = ?
+		)`, f.Favorited.Id)
+	}
+
+	if f.Query != "" {
+		q = q.Where("a.search @@ websearch_to_tsquery('english', ?)", f.Query)
+	}
+
+	return q
+}
+
+// CountArticles returns total number of articles matching the filter
+// regardless of its limit and offset
+func (s Store) CountArticles(ctx context.Context, f *app.ArticleListFilter) (int, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	q := psql.Select("count(*)").From("articles a")
+	q = whereArticleFilter(q, f)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build count query")
+	}
+
+	var count int
+	err = s.db.QueryRowxContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to count articles")
+	}
+
+	return count, nil
+}
+
+// Get returns a single article by its slug. Viewer is optional, when it's set
+// the following and favorited fields are filled for it.
+func (s Store) GetArticle(ctx context.Context, slug string, viewer *app.Profile) (*app.Article, error) {
+	// If viewer is not set then id will be 0 and "following" and "favorited"
+	// will always be false because ids start with 1.
+	viewerId := 0
+	if viewer != nil {
+		viewerId = viewer.Id
+	}
+
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	query, args, err :=
 		psql.Select(`
+				a.id as id,
 				a.title as title,
 				a.description as description,
 				a.body as body,
 				a.created as created,
 				a.updated as updated,
+				a.version as version,
 				a.author_id as author_id,
 				u.name as author_name,
 				u.bio as bio,
 				u.image as image,
-				f.followee != 0 as following
+				f.followee IS NOT NULL as following
 			`).
+			Column(tagListColumn).
+			Column(favoritedColumn(viewerId)).
+			Column(favoritesCountColumn).
 			From("articles a").
 			Join("users u on (a.author_id = u.id)").
-			LeftJoin("followers f on (u.id = f.followee)").
+			LeftJoin("followers f on (u.id = f.followee AND f.follower = ?)", viewerId).
 			Where(sq.Eq{"a.slug": slug}).
 			ToSql()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to build select query")
 	}
 
-	row := s.db.QueryRowx(query, args...)
+	row := s.db.QueryRowxContext(ctx, query, args...)
 
 	// TODO: use PostgresArticle with sqlx.StructScan
 	var title, authorName string
-	var authorId int
+	var id, version, authorId int
 	var description, body, bio, image sql.NullString
 	var created, updated time.Time
-	var following sql.NullBool
+	var following, favorited sql.NullBool
+	var tagList pq.StringArray
+	var favoritesCount int
 
 	err = row.Scan(
-		&title, &description, &body, &created, &updated,
-		&authorId, &authorName, &bio, &image, &following,
+		&id, &title, &description, &body, &created, &updated, &version,
+		&authorId, &authorName, &bio, &image, &following, &tagList,
+		&favorited, &favoritesCount,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -159,12 +309,14 @@ func (s Store) GetArticle(slug string) (*app.Article, error) {
 	}
 
 	article := app.Article{
+		Id:          id,
 		Slug:        slug,
 		Title:       title,
 		Description: description.String,
 		Body:        body.String,
 		Created:     created,
 		Updated:     updated,
+		Version:     version,
 		Author: app.Profile{
 			Id:        authorId,
 			Name:      authorName,
@@ -172,32 +324,52 @@ func (s Store) GetArticle(slug string) (*app.Article, error) {
 			Image:     image.String,
 			Following: following.Bool,
 		},
+		TagList:        []string(tagList),
+		Favorited:      favorited.Bool,
+		FavoritesCount: favoritesCount,
 	}
 
 	return &article, nil
 }
 
-func (s Store) CreateArticle(a *app.Article) error {
+func (s Store) CreateArticle(ctx context.Context, a *app.Article) error {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	query, args, err :=
 		psql.
 			Insert("articles").
 			Columns("slug", "title", "description", "body", "author_id", "created", "updated").
 			Values(a.Slug, a.Title, a.Description, a.Body, a.Author.Id, a.Created, a.Updated).
+			Suffix("RETURNING id, version").
 			ToSql()
 	if err != nil {
 		return errors.Wrap(err, "failed to build insert query")
 	}
 
-	_, err = s.db.Exec(query, args...)
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowxContext(ctx, query, args...).Scan(&a.Id, &a.Version)
 	if err != nil {
 		return errors.Wrap(err, "failed to execute insert query")
 	}
 
+	err = setArticleTags(ctx, tx, a.Id, a.TagList)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
 	return nil
 }
 
-func (s Store) DeleteArticle(id int) error {
+func (s Store) DeleteArticle(ctx context.Context, id int) error {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	query, args, err :=
 		psql.
@@ -208,7 +380,7 @@ func (s Store) DeleteArticle(id int) error {
 		return errors.Wrap(err, "failed to build delete query")
 	}
 
-	_, err = s.db.Exec(query, args...)
+	_, err = s.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return errors.Wrap(err, "failed to execute delete query")
 	}
@@ -216,22 +388,130 @@ func (s Store) DeleteArticle(id int) error {
 	return nil
 }
 
-func (s Store) UpdateArticle(a *app.Article) error {
+func (s Store) UpdateArticle(ctx context.Context, a *app.Article) error {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	query, args, err :=
 		psql.
 			Update("articles").
 			SetMap(a.UpdateMap()).
-			Where(sq.Eq{"id": a.Id}).
+			Set("version", sq.Expr("version + 1")).
+			Where(sq.Eq{"id": a.Id, "version": a.Version}).
 			ToSql()
 	if err != nil {
 		return errors.Wrap(err, "failed to build update query")
 	}
 
-	_, err = s.db.Exec(query, args...)
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
 		return errors.Wrap(err, "failed to execute update query")
 	}
 
+	// Nothing is updated when article was modified after it was read
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get number of updated rows")
+	}
+	if n == 0 {
+		return app.ErrorArticleVersionMismatch
+	}
+	a.Version++
+
+	err = setArticleTags(ctx, tx, a.Id, a.TagList)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// setArticleTags replaces tags of the article with the given list. Tags that
+// don't exist yet are created.
+func setArticleTags(ctx context.Context, tx *sqlx.Tx, articleId int, tags []string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM article_tags WHERE article_id = $1`, articleId)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete article tags")
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO tags (name)
+		SELECT unnest($1::text[])
+		ON CONFLICT (name) DO NOTHING
+	`
+	_, err = tx.ExecContext(ctx, query, pq.Array(tags))
+	if err != nil {
+		return errors.Wrap(err, "failed to insert tags")
+	}
+
+	query = `
+		INSERT INTO article_tags (article_id, tag_id)
+		SELECT $1, id FROM tags WHERE name = ANY($2)
+	`
+	_, err = tx.ExecContext(ctx, query, articleId, pq.Array(tags))
+	if err != nil {
+		return errors.Wrap(err, "failed to insert article tags")
+	}
+
+	return nil
+}
+
+func (s Store) FavoriteArticle(ctx context.Context, user *app.Profile, a *app.Article) error {
+	query := `
+		INSERT INTO favorites (user_id, article_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err := s.db.ExecContext(ctx, query, user.Id, a.Id)
+	if err != nil {
+		return errors.Wrap(err, "failed to add favorite to db")
+	}
+
 	return nil
 }
+
+func (s Store) UnfavoriteArticle(ctx context.Context, user *app.Profile, a *app.Article) error {
+	query := `
+		DELETE FROM favorites
+		WHERE user_id = $1 AND article_id = $2
+	`
+
+	_, err := s.db.ExecContext(ctx, query, user.Id, a.Id)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete favorite from db")
+	}
+
+	return nil
+}
+
+// ListTags returns names of all tags that are used by at least one article
+func (s Store) ListTags(ctx context.Context) ([]string, error) {
+	query := `
+		SELECT DISTINCT t.name
+		FROM tags t
+		JOIN article_tags at ON (at.tag_id = t.id)
+		ORDER BY t.name
+	`
+
+	tags := []string{}
+	err := s.db.SelectContext(ctx, &tags, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query tags")
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,289 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/db"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migrationsLock is the advisory lock key that serializes migrations of
+// concurrently started instances
+const migrationsLock = 0x636f6e64756974 // "conduit"
+
+// Migrations returns embedded migrations sorted by version
+func Migrations() ([]db.Migration, error) {
+	return db.LoadMigrations(migrationsFS, "migrations")
+}
+
+// Migrate applies pending migrations. It's safe to call from multiple
+// instances at once because migrations are applied under advisory lock.
+func (s *Store) Migrate(ctx context.Context) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+
+	return s.withMigrationLock(ctx, func(conn *sqlx.Conn) error {
+		applied, err := appliedMigrations(ctx, conn, migrations)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+
+			err = applyMigration(ctx, conn, m.Up, func(tx *sqlx.Tx) error {
+				_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name)
+				return err
+			})
+			if err != nil {
+				return errors.Wrapf(err, "failed to apply migration %d_%s", m.Version, m.Name)
+			}
+		}
+
+		return nil
+	})
+}
+
+// MigrateDown reverts n last applied migrations
+func (s *Store) MigrateDown(ctx context.Context, n int) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+
+	return s.withMigrationLock(ctx, func(conn *sqlx.Conn) error {
+		applied, err := appliedMigrations(ctx, conn, migrations)
+		if err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && n > 0; i-- {
+			m := migrations[i]
+			if _, ok := applied[m.Version]; !ok {
+				continue
+			}
+
+			if m.Down == "" {
+				return errors.Errorf("migration %d_%s has no down file", m.Version, m.Name)
+			}
+
+			err = applyMigration(ctx, conn, m.Down, func(tx *sqlx.Tx) error {
+				_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version)
+				return err
+			})
+			if err != nil {
+				return errors.Wrapf(err, "failed to revert migration %d_%s", m.Version, m.Name)
+			}
+
+			n--
+		}
+
+		return nil
+	})
+}
+
+// MigrationStatus returns all of the embedded migrations with the time they
+// were applied
+func (s *Store) MigrationStatus(ctx context.Context) ([]db.MigrationStatus, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []db.MigrationStatus
+	err = s.withMigrationLock(ctx, func(conn *sqlx.Conn) error {
+		applied, err := appliedMigrations(ctx, conn, migrations)
+		if err != nil {
+			return err
+		}
+
+		statuses = db.Statuses(migrations, applied)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+// withMigrationLock calls f with a connection holding migrations lock.
+// Advisory locks are held by the session, so all of the migration queries
+// must be made on this connection.
+func (s *Store) withMigrationLock(ctx context.Context, f func(conn *sqlx.Conn) error) error {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get db connection")
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationsLock)
+	if err != nil {
+		return errors.Wrap(err, "failed to take migrations lock")
+	}
+
+	// Unlock with the new context so the lock is released even when ctx is
+	// cancelled. Closing the connection would release it anyway but the
+	// connection is returned to the pool.
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationsLock)
+
+	err = createMigrationsTable(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	return f(conn)
+}
+
+// createMigrationsTable creates schema_migrations table. Table of
+// golang-migrate tool that was used before is converted to the new one.
+func createMigrationsTable(ctx context.Context, conn *sqlx.Conn) error {
+	var legacy bool
+	err := conn.GetContext(ctx, &legacy, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM information_schema.columns
+			WHERE
+				table_schema = current_schema() AND
+				table_name = 'schema_migrations' AND
+				column_name = 'dirty'
+		)
+	`)
+	if err != nil {
+		return errors.Wrap(err, "failed to check migrations table")
+	}
+
+	if !legacy {
+		_, err = conn.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS schema_migrations (
+				version int PRIMARY KEY,
+				name text NOT NULL,
+				applied_at timestamptz NOT NULL DEFAULT now()
+			)
+		`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create migrations table")
+		}
+
+		return nil
+	}
+
+	var version int
+	var dirty bool
+	err = conn.QueryRowxContext(ctx, `SELECT version, dirty FROM schema_migrations`).Scan(&version, &dirty)
+	if err != nil && err != sql.ErrNoRows {
+		return errors.Wrap(err, "failed to get golang-migrate version")
+	}
+
+	if dirty {
+		return errors.Errorf("golang-migrate migration %d is dirty, fix it manually", version)
+	}
+
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		DROP TABLE schema_migrations;
+		CREATE TABLE schema_migrations (
+			version int PRIMARY KEY,
+			name text NOT NULL,
+			applied_at timestamptz NOT NULL DEFAULT now()
+		);
+	`)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert golang-migrate table")
+	}
+
+	for _, m := range migrations {
+		if m.Version > version {
+			break
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name)
+		if err != nil {
+			return errors.Wrap(err, "failed to convert golang-migrate table")
+		}
+	}
+
+	return errors.Wrap(tx.Commit(), "failed to convert golang-migrate table")
+}
+
+// appliedMigrations returns applied migration versions with the time they were
+// applied. It fails if database has migrations unknown to this binary, i.e.
+// the binary is older than the schema.
+func appliedMigrations(ctx context.Context, conn *sqlx.Conn, migrations []db.Migration) (map[int]time.Time, error) {
+	rows, err := conn.QueryxContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query applied migrations")
+	}
+	defer rows.Close()
+
+	known := make(map[int]bool, len(migrations))
+	for _, m := range migrations {
+		known[m.Version] = true
+	}
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var t time.Time
+		err = rows.Scan(&version, &t)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan applied migration")
+		}
+
+		if !known[version] {
+			return nil, errors.Errorf("unknown migration %d is applied, database schema is newer than the binary", version)
+		}
+
+		applied[version] = t
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to query applied migrations")
+	}
+
+	return applied, nil
+}
+
+// applyMigration executes migration query and records the change with record
+// in the same transaction
+func applyMigration(ctx context.Context, conn *sqlx.Conn, query string, record func(tx *sqlx.Tx) error) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	err = record(tx)
+	if err != nil {
+		return errors.Wrap(err, "failed to record migration")
+	}
+
+	return tx.Commit()
+}
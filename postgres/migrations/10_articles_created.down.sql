@@ -0,0 +1 @@
+DROP INDEX IF EXISTS articles_created_id_idx;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS articles_created_id_idx ON articles (created DESC, id DESC);
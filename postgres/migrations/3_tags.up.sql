@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS tags (
+    id int GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name text UNIQUE NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS article_tags (
+    article_id int NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    tag_id int NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    UNIQUE(article_id, tag_id)
+);
+
+CREATE INDEX article_tags_tag_id_idx ON article_tags (tag_id);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS favorites (
+    user_id int NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    article_id int NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    UNIQUE(user_id, article_id)
+);
+
+CREATE INDEX favorites_article_id_idx ON favorites (article_id);
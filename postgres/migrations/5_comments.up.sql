@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS comments (
+    id int GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    article_id int NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    author_id int NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    body text NOT NULL,
+    created timestamptz NOT NULL DEFAULT NOW(),
+    updated timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX comments_article_id_idx ON comments (article_id);
@@ -0,0 +1 @@
+ALTER TABLE articles ADD COLUMN version int NOT NULL DEFAULT 1;
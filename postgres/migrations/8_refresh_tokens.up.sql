@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    hash text PRIMARY KEY,
+    user_id int NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created timestamptz NOT NULL DEFAULT NOW(),
+    expires timestamptz NOT NULL
+);
+
+CREATE INDEX refresh_tokens_user_id_idx ON refresh_tokens (user_id);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    id text PRIMARY KEY,
+    expires timestamptz NOT NULL
+);
+
+CREATE INDEX revoked_tokens_expires_idx ON revoked_tokens (expires);
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/pkg/errors"
@@ -18,7 +19,7 @@ type PostgresProfile struct {
 	Following bool
 }
 
-func (s *Store) GetProfile(username string, follower *app.Profile) (*app.Profile, error) {
+func (s *Store) GetProfile(ctx context.Context, username string, follower *app.Profile) (*app.Profile, error) {
 	query := `
 		SELECT
 			id,
@@ -40,7 +41,7 @@ func (s *Store) GetProfile(username string, follower *app.Profile) (*app.Profile
 		followerId = follower.Id
 	}
 
-	row := s.db.QueryRowx(query, followerId, username)
+	row := s.db.QueryRowxContext(ctx, query, followerId, username)
 
 	var p PostgresProfile
 	err := row.StructScan(&p)
@@ -61,14 +62,14 @@ func (s *Store) GetProfile(username string, follower *app.Profile) (*app.Profile
 	return &profile, nil
 }
 
-func (s Store) FollowProfile(follower, followee *app.Profile) error {
+func (s Store) FollowProfile(ctx context.Context, follower, followee *app.Profile) error {
 	query := `
 		INSERT INTO followers (follower, followee)
 		VALUES ($1, $2)
 		ON CONFLICT DO NOTHING
 	`
 
-	_, err := s.db.Exec(query, follower.Id, followee.Id)
+	_, err := s.db.ExecContext(ctx, query, follower.Id, followee.Id)
 	if err != nil {
 		return errors.Wrap(err, "failed to add follow relationship to db")
 	}
@@ -76,13 +77,13 @@ func (s Store) FollowProfile(follower, followee *app.Profile) error {
 	return nil
 }
 
-func (s Store) UnfollowProfile(follower, followee *app.Profile) error {
+func (s Store) UnfollowProfile(ctx context.Context, follower, followee *app.Profile) error {
 	query := `
 		DELETE FROM followers
 		WHERE follower = $1 AND followee = $2
 	`
 
-	_, err := s.db.Exec(query, follower.Id, followee.Id)
+	_, err := s.db.ExecContext(ctx, query, follower.Id, followee.Id)
 	if err != nil {
 		return errors.Wrap(err, "failed to delete follow relationship from db")
 	}
@@ -1,8 +1,8 @@
 package postgres
 
 import (
-	"fmt"
-	"time"
+	"context"
+	"database/sql"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
@@ -14,61 +14,30 @@ type Store struct {
 	db *sqlx.DB
 }
 
-func NewStore(DSN string) (*Store, error) {
-	db, err := db.ConnectLoop("postgres", DSN, 1*time.Minute)
+// NewStore connects to Postgres at DSN. Connection is retried and the pool is
+// limited according to the config.
+func NewStore(DSN string, config db.Config) (*Store, error) {
+	conn, err := db.ConnectLoop("postgres", DSN, config)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to connect to users db")
 	}
 
-	db.MapperFunc(CamelToSnakeASCII)
+	conn.MapperFunc(db.CamelToSnakeASCII)
 
-	return &Store{db: db}, nil
+	return &Store{db: conn}, nil
 }
 
-// CamelToSnakeASCII converts camel case strings to snake case.
-// It's used as a mapper for sqlx.
-// It's a simplified version of the same name function found in
-// https://github.com/scylladb/go-reflectx.
-func CamelToSnakeASCII(s string) string {
-	buf := []byte(s)
-	out := make([]byte, 0, len(buf)+3)
-
-	l := len(buf)
-	for i := 0; i < l; i++ {
-		if !(allowedChar(buf[i]) || buf[i] == '_') {
-			panic(fmt.Sprint("not allowed name ", s))
-		}
-
-		b := buf[i]
-
-		if isUpper(b) {
-			if i > 0 && buf[i-1] != '_' && (isLower(buf[i-1]) || (i+1 < l && isLower(buf[i+1]))) {
-				out = append(out, '_')
-			}
-			b = toLower(b)
-		}
-
-		out = append(out, b)
-	}
-
-	return string(out)
+// Ping checks that database is reachable
+func (s *Store) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
 }
 
-func isUpper(b byte) bool {
-	return (b >= 'A' && b <= 'Z')
-}
-
-func isLower(b byte) bool {
-	return !isUpper(b)
-}
-
-func toLower(b byte) byte {
-	if isUpper(b) {
-		return b + 32
-	}
-	return b
+// Stats returns connection pool statistics
+func (s *Store) Stats() sql.DBStats {
+	return s.db.Stats()
 }
 
-func allowedChar(b byte) bool {
-	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+// Close closes all of the connections
+func (s *Store) Close() error {
+	return s.db.Close()
 }
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/dzeban/conduit/db"
+	"github.com/dzeban/conduit/storetest"
+)
+
+// testDSNEnv is the environment variable with the database for tests. Tests
+// that need Postgres are skipped when it's not set. All of the data in the
+// database is deleted.
+const testDSNEnv = "CONDUIT_TEST_POSTGRES_DSN"
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	s, err := NewStore(os.Getenv(testDSNEnv), db.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	ctx := context.Background()
+	if err := s.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		TRUNCATE
+			users, articles, followers, tags, article_tags, favorites,
+			comments, refresh_tokens, revoked_tokens
+		RESTART IDENTITY CASCADE
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestStore(t *testing.T) {
+	if os.Getenv(testDSNEnv) == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+
+	storetest.Run(t, func(t *testing.T) storetest.Store {
+		return newTestStore(t)
+	})
+}
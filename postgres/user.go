@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 
 	sq "github.com/Masterminds/squirrel"
@@ -10,7 +11,7 @@ import (
 )
 
 // GetUser returns user by email from Postgres store
-func (s *Store) GetUser(email string) (*app.User, error) {
+func (s *Store) GetUser(ctx context.Context, email string) (*app.User, error) {
 	query := `
 		SELECT
 			id,
@@ -24,7 +25,7 @@ func (s *Store) GetUser(email string) (*app.User, error) {
 			email = $1
 	`
 
-	row := s.db.QueryRowx(query, email)
+	row := s.db.QueryRowxContext(ctx, query, email)
 
 	// Scan the row using simple Scan method.
 	// We can't use StructScan to the app.User var because bio and image may be
@@ -52,7 +53,7 @@ func (s *Store) GetUser(email string) (*app.User, error) {
 	return &user, nil
 }
 
-func (s *Store) GetUserById(id int) (*app.User, error) {
+func (s *Store) GetUserById(ctx context.Context, id int) (*app.User, error) {
 	query := `
 		SELECT
 			name,
@@ -66,7 +67,7 @@ func (s *Store) GetUserById(id int) (*app.User, error) {
 			id = $1
 	`
 
-	row := s.db.QueryRowx(query, id)
+	row := s.db.QueryRowxContext(ctx, query, id)
 
 	// Scan the row using simple Scan method.
 	// We can't use StructScan to the app.User var because bio and image may be
@@ -93,14 +94,17 @@ func (s *Store) GetUserById(id int) (*app.User, error) {
 	return &user, nil
 }
 
-// AddUser adds new user to the Postgres user store and returns it
-func (s *Store) AddUser(user *app.User) error {
+// AddUser adds new user to the Postgres user store and sets its id
+func (s *Store) AddUser(ctx context.Context, user *app.User) error {
 	query := `
 		INSERT INTO users (name, email, password_hash, bio, image)
-		VALUES (:name, :email, :password_hash, :bio, :image)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
 	`
 
-	_, err := s.db.NamedExec(query, &user)
+	err := s.db.
+		QueryRowxContext(ctx, query, user.Name, user.Email, user.PasswordHash, user.Bio, user.Image).
+		Scan(&user.Id)
 	if err != nil {
 		return errors.Wrap(err, "failed to insert user to db")
 	}
@@ -109,7 +113,7 @@ func (s *Store) AddUser(user *app.User) error {
 }
 
 // UpdateUser modifies user by email and return updated user object
-func (s *Store) UpdateUser(user *app.User) error {
+func (s *Store) UpdateUser(ctx context.Context, user *app.User) error {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	query, args, err :=
 		psql.Update("users").
@@ -121,7 +125,7 @@ func (s *Store) UpdateUser(user *app.User) error {
 	}
 
 	// Execute update.
-	_, err = s.db.Exec(query, args...)
+	_, err = s.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return errors.Wrap(err, "failed to execute update query")
 	}
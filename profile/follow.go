@@ -1,27 +1,29 @@
 package profile
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 
 	"github.com/dzeban/conduit/app"
 )
 
-func (s *Service) Follow(follower *app.User, username string) (*app.Profile, error) {
-	followee, err := s.Get(username, follower)
+func (s *Service) Follow(ctx context.Context, follower *app.User, username string) (*app.Profile, error) {
+	followee, err := s.Get(ctx, username, follower)
 	if err != nil {
-		return nil, app.ServiceError(errors.Wrap(err, "failed to get followee profile"))
+		return nil, err
 	}
 
 	if followee.Following {
-		return nil, app.ServiceError(errorProfileAlreadyFollowing)
+		return nil, app.ConflictError(errorProfileAlreadyFollowing)
 	}
 
-	err = s.store.FollowProfile(app.ProfileFromUser(follower), followee)
+	err = s.store.FollowProfile(ctx, app.ProfileFromUser(follower), followee)
 	if err != nil {
 		return nil, app.InternalError(errors.Wrap(err, "failed to follow profile"))
 	}
 
-	p, err := s.Get(username, follower)
+	p, err := s.Get(ctx, username, follower)
 	if err != nil {
 		return nil, app.InternalError(app.ErrorProfileNotFound)
 	}
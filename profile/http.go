@@ -17,19 +17,19 @@ type Server struct {
 	service *Service
 }
 
-func NewHTTP(store Store, secret []byte) (*Server, error) {
+func NewHTTP(store Store, signer *jwt.Signer, authOpts ...jwt.AuthOption) (*Server, error) {
 	s := &Server{
 		router:  chi.NewRouter(),
 		service: NewService(store),
 	}
 
 	s.router.
-		With(jwt.Auth(secret, jwt.AuthTypeOptional)).
+		With(jwt.Auth(signer, jwt.AuthTypeOptional, authOpts...)).
 		Get("/{username}", transport.WithError(s.HandleGet))
 
 	// Endpoints protected by JWT auth
 	s.router.Group(func(r chi.Router) {
-		r.Use(jwt.Auth(secret, jwt.AuthTypeRequired))
+		r.Use(jwt.Auth(signer, jwt.AuthTypeRequired, authOpts...))
 
 		r.Post("/{username}/follow", transport.WithError(s.HandleFollow))
 		r.Delete("/{username}/follow", transport.WithError(s.HandleUnfollow))
@@ -52,7 +52,7 @@ func (s *Server) HandleGet(w http.ResponseWriter, r *http.Request) error {
 
 	currentUser, _ := app.UserFromContext(r.Context())
 
-	p, err := s.service.Get(username, currentUser)
+	p, err := s.service.Get(r.Context(), username, currentUser)
 	if err != nil {
 		return err
 	}
@@ -71,7 +71,7 @@ func (s *Server) HandleFollow(w http.ResponseWriter, r *http.Request) error {
 
 	currentUser, _ := app.UserFromContext(r.Context())
 
-	p, err := s.service.Follow(currentUser, username)
+	p, err := s.service.Follow(r.Context(), currentUser, username)
 	if err != nil {
 		return err
 	}
@@ -90,7 +90,7 @@ func (s *Server) HandleUnfollow(w http.ResponseWriter, r *http.Request) error {
 
 	currentUser, _ := app.UserFromContext(r.Context())
 
-	p, err := s.service.Unfollow(currentUser, username)
+	p, err := s.service.Unfollow(r.Context(), currentUser, username)
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package profile
 
 import (
+	"context"
 	"errors"
 
 	"github.com/dzeban/conduit/app"
@@ -16,9 +17,9 @@ type Service struct {
 }
 
 type Store interface {
-	GetProfile(username string, follower *app.Profile) (*app.Profile, error)
-	FollowProfile(follower, followee *app.Profile) error
-	UnfollowProfile(follower, followee *app.Profile) error
+	GetProfile(ctx context.Context, username string, follower *app.Profile) (*app.Profile, error)
+	FollowProfile(ctx context.Context, follower, followee *app.Profile) error
+	UnfollowProfile(ctx context.Context, follower, followee *app.Profile) error
 }
 
 func NewService(store Store) *Service {
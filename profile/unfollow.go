@@ -1,27 +1,29 @@
 package profile
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 
 	"github.com/dzeban/conduit/app"
 )
 
-func (s *Service) Unfollow(follower *app.User, username string) (*app.Profile, error) {
-	followee, err := s.Get(username, follower)
+func (s *Service) Unfollow(ctx context.Context, follower *app.User, username string) (*app.Profile, error) {
+	followee, err := s.Get(ctx, username, follower)
 	if err != nil {
-		return nil, app.ServiceError(errors.Wrap(err, "failed to get followee profile"))
+		return nil, err
 	}
 
 	if !followee.Following {
-		return nil, app.ServiceError(errorProfileAlreadyNotFollowing)
+		return nil, app.ConflictError(errorProfileAlreadyNotFollowing)
 	}
 
-	err = s.store.UnfollowProfile(app.ProfileFromUser(follower), followee)
+	err = s.store.UnfollowProfile(ctx, app.ProfileFromUser(follower), followee)
 	if err != nil {
 		return nil, app.InternalError(errors.Wrap(err, "failed to unfollow profile"))
 	}
 
-	p, err := s.Get(username, follower)
+	p, err := s.Get(ctx, username, follower)
 	if err != nil {
 		return nil, app.InternalError(app.ErrorProfileNotFound)
 	}
@@ -0,0 +1,121 @@
+// Package search implements naive full-text search for the stores that don't
+// have a native one. Query is split into words that are matched as case
+// insensitive substrings, so there is no stemming or ranking by frequency.
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// snippetContext is the number of bytes around the match kept in snippet
+const snippetContext = 30
+
+// Terms splits search query into lowercase words
+func Terms(query string) []string {
+	return strings.Fields(strings.ToLower(query))
+}
+
+// Rank returns relevance of the article fields to the terms. All of the terms
+// must be found, matches in title weigh more than in description and body.
+// Zero rank means no match.
+func Rank(title, description, body string, terms []string) int {
+	title = strings.ToLower(title)
+	description = strings.ToLower(description)
+	body = strings.ToLower(body)
+
+	rank := 0
+	for _, term := range terms {
+		termRank := 0
+		if strings.Contains(title, term) {
+			termRank += 3
+		}
+		if strings.Contains(description, term) {
+			termRank += 2
+		}
+		if strings.Contains(body, term) {
+			termRank += 1
+		}
+
+		if termRank == 0 {
+			return 0
+		}
+
+		rank += termRank
+	}
+
+	return rank
+}
+
+// Snippet returns text fragment around the first term match. Every term match
+// in the fragment is highlighted like Postgres ts_headline does. Snippet is
+// HTML, so the text is escaped and only the highlighting is markup.
+func Snippet(text string, terms []string) string {
+	// Offsets in lowercase text are valid for the original text only when
+	// lowercasing kept the length
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		lower = text
+	}
+
+	i, n := firstMatch(lower, terms, 0)
+	if i < 0 {
+		return html.EscapeString(text[:runeBoundary(text, 2*snippetContext)])
+	}
+
+	start := i - snippetContext
+	if start < 0 {
+		start = 0
+	}
+	start = runeBoundary(text, start)
+	end := runeBoundary(text, i+n+snippetContext)
+
+	var b strings.Builder
+	for pos := start; ; {
+		i, n = firstMatch(lower[:end], terms, pos)
+		if i < 0 {
+			b.WriteString(html.EscapeString(text[pos:end]))
+			break
+		}
+
+		b.WriteString(html.EscapeString(text[pos:i]))
+		b.WriteString("<b>" + html.EscapeString(text[i:i+n]) + "</b>")
+		pos = i + n
+	}
+
+	return b.String()
+}
+
+// firstMatch returns offset and length of the first term match in text
+// starting from offset from. The longest term wins when several terms match
+// at the same offset. Offset is -1 when nothing matches.
+func firstMatch(text string, terms []string, from int) (int, int) {
+	i, n := -1, 0
+	for _, term := range terms {
+		j := strings.Index(text[from:], term)
+		if j < 0 {
+			continue
+		}
+
+		j += from
+		if i < 0 || j < i || (j == i && len(term) > n) {
+			i, n = j, len(term)
+		}
+	}
+
+	return i, n
+}
+
+// runeBoundary returns the closest offset not greater than i that doesn't
+// split a rune in text
+func runeBoundary(text string, i int) int {
+	if i >= len(text) {
+		return len(text)
+	}
+
+	for i > 0 && !utf8.RuneStart(text[i]) {
+		i--
+	}
+	return i
+}
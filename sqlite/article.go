@@ -0,0 +1,485 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+	"github.com/dzeban/conduit/search"
+)
+
+// tagSeparator separates tag names aggregated into a single column. It's the
+// ASCII unit separator that is not expected in tag names.
+const tagSeparator = "\x1f"
+
+// sqliteArticle is the same as app.Article but specific for articles
+// queries. It expands Author type to its fields and uses sql.Null* types.
+// Tags are aggregated into a single string because SQLite has no arrays.
+type sqliteArticle struct {
+	Id          int
+	Slug        string
+	Title       string
+	Description sql.NullString
+	Body        sql.NullString
+	Created     time.Time
+	Updated     time.Time
+	Version     int
+	AuthorId    int
+	AuthorName  string
+	AuthorBio   sql.NullString
+	AuthorImage sql.NullString
+	Following   bool
+	TagList     sql.NullString
+
+	Favorited      bool
+	FavoritesCount int
+}
+
+func (a sqliteArticle) toArticle() *app.Article {
+	tags := []string{}
+	if a.TagList.String != "" {
+		tags = strings.Split(a.TagList.String, tagSeparator)
+	}
+
+	return &app.Article{
+		Id:          a.Id,
+		Slug:        a.Slug,
+		Title:       a.Title,
+		Description: a.Description.String,
+		Body:        a.Body.String,
+		Created:     a.Created,
+		Updated:     a.Updated,
+		Version:     a.Version,
+		Author: app.Profile{
+			Id:        a.AuthorId,
+			Name:      a.AuthorName,
+			Bio:       a.AuthorBio.String,
+			Image:     a.AuthorImage.String,
+			Following: a.Following,
+		},
+		TagList:        tags,
+		Favorited:      a.Favorited,
+		FavoritesCount: a.FavoritesCount,
+	}
+}
+
+// tagListColumn is a subquery column that aggregates sorted article tags into
+// a string. It expects articles table to be aliased as "a".
+const tagListColumn = `
+	(
+		SELECT group_concat(name, char(31))
+		FROM (
+			SELECT t.name
+			FROM article_tags at
+			JOIN tags t ON (at.tag_id = t.id)
+			WHERE at.article_id = a.id
+			ORDER BY t.name
+		)
+	) as tag_list
+`
+
+// favoritesCountColumn is a subquery column that counts how many users
+// favorited the article. It expects articles table to be aliased as "a".
+const favoritesCountColumn = `
+	(SELECT count(*) FROM favorites fav WHERE fav.article_id = a.id) as favorites_count
+`
+
+// favoritedColumn returns a subquery column that tells whether the article is
+// favorited by the user with a given id. It expects articles table to be
+// aliased as "a".
+func favoritedColumn(userId int) sq.Sqlizer {
+	return sq.Expr(`
+		EXISTS (
+			SELECT 1 FROM favorites fav
+			WHERE fav.article_id = a.id AND fav.user_id = ?
+		) as favorited
+	`, userId)
+}
+
+// selectArticles returns query for articles with all of the app.Article
+// fields. Following and favorited are filled for the viewer with a given id.
+func selectArticles(viewerId int) sq.SelectBuilder {
+	return sq.Select(`
+				a.id as id,
+				a.slug as slug,
+				a.title as title,
+				a.description as description,
+				a.body as body,
+				a.created as created,
+				a.updated as updated,
+				a.version as version,
+				a.author_id as author_id,
+				u.name as author_name,
+				u.bio as author_bio,
+				u.image as author_image,
+				f.followee IS NOT NULL as following
+			`).
+		Column(tagListColumn).
+		Column(favoritedColumn(viewerId)).
+		Column(favoritesCountColumn).
+		From("articles a").
+		Join("users u on (a.author_id = u.id)").
+		LeftJoin("followers f on (u.id = f.followee AND f.follower = ?)", viewerId)
+}
+
+// termRank returns expression with relevance of the article to the search
+// term. Matches are weighed the same way as in search.Rank. Term is expected
+// to be lowercase.
+func termRank(term string) (string, []interface{}) {
+	expr := `(
+		(instr(lower(a.title), ?) > 0) * 3 +
+		(instr(lower(coalesce(a.description, '')), ?) > 0) * 2 +
+		(instr(lower(coalesce(a.body, '')), ?) > 0)
+	)`
+
+	return expr, []interface{}{term, term, term}
+}
+
+// ListArticles returns articles matching the filter sorted from the newest to
+// the oldest. Search results are sorted by relevance first.
+func (s *Store) ListArticles(ctx context.Context, f *app.ArticleListFilter) ([]*app.Article, error) {
+	// If viewer is not set then id will be 0 and "following" and "favorited"
+	// will always be false because ids start with 1.
+	viewerId := 0
+	if f.CurrentUser != nil {
+		viewerId = f.CurrentUser.Id
+	}
+
+	q := whereArticleFilter(selectArticles(viewerId), f)
+
+	terms := search.Terms(f.Query)
+	if len(terms) > 0 {
+		var ranks []string
+		var args []interface{}
+		for _, term := range terms {
+			rank, termArgs := termRank(term)
+			ranks = append(ranks, rank)
+			args = append(args, termArgs...)
+		}
+
+		q = q.OrderByClause(strings.Join(ranks, " + ")+" DESC", args...)
+	}
+	q = q.OrderBy("a.created DESC", "a.id DESC")
+
+	// Keyset pagination. It's applied here and not in whereArticleFilter
+	// because it must not affect the total count of articles.
+	if f.After != nil {
+		q = q.Where("(a.created, a.id) < (?, ?)", timestamp(f.After.Created), f.After.Id)
+	}
+
+	query, args, err := q.Limit(f.Limit).Offset(f.Offset).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build select query")
+	}
+
+	rows, err := s.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query articles")
+	}
+	defer rows.Close()
+
+	var articles []*app.Article
+	for rows.Next() {
+		var a sqliteArticle
+		err := rows.StructScan(&a)
+		if err != nil {
+			return nil, errors.Wrap(err, "row scan failed")
+		}
+
+		article := a.toArticle()
+		if len(terms) > 0 {
+			article.Snippet = search.Snippet(article.Body, terms)
+		}
+
+		articles = append(articles, article)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows scan error")
+	}
+
+	return articles, nil
+}
+
+// whereArticleFilter adds conditions from the filter to the articles query.
+// Limit and offset are not applied here. It expects articles table to be
+// aliased as "a".
+func whereArticleFilter(q sq.SelectBuilder, f *app.ArticleListFilter) sq.SelectBuilder {
+	if f.FeedOf != nil {
+		q = q.Where(`EXISTS (
+			SELECT 1 FROM followers ff
+			WHERE ff.followee = a.author_id AND ff.follower = ?
+		)`, f.FeedOf.Id)
+	}
+
+	if f.Author != nil {
+		q = q.Where("a.author_id = ?", f.Author.Id)
+	}
+
+	if f.Tag != "" {
+		q = q.Where(`EXISTS (
+			SELECT 1
+			FROM article_tags at
+			JOIN tags t ON (at.tag_id = t.id)
+			WHERE at.article_id = a.id AND t.name = ?
+		)`, f.Tag)
+	}
+
+	if f.Favorited != nil {
+		q = q.Where(`EXISTS (
+			SELECT 1 FROM favorites fav
+			WHERE fav.article_id = a.id AND fav.user_id = ?
+		)`, f.Favorited.Id)
+	}
+
+	// All of the search terms must be found
+	for _, term := range search.Terms(f.Query) {
+		rank, args := termRank(term)
+		q = q.Where(rank+" > 0", args...)
+	}
+
+	return q
+}
+
+// CountArticles returns total number of articles matching the filter
+// regardless of its limit and offset
+func (s *Store) CountArticles(ctx context.Context, f *app.ArticleListFilter) (int, error) {
+	q := whereArticleFilter(sq.Select("count(*)").From("articles a"), f)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build count query")
+	}
+
+	var count int
+	err = s.db.QueryRowxContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to count articles")
+	}
+
+	return count, nil
+}
+
+// GetArticle returns a single article by its slug or nil if there is no such
+// article. Viewer is optional, when it's set the following and favorited
+// fields are filled for it.
+func (s *Store) GetArticle(ctx context.Context, slug string, viewer *app.Profile) (*app.Article, error) {
+	viewerId := 0
+	if viewer != nil {
+		viewerId = viewer.Id
+	}
+
+	query, args, err := selectArticles(viewerId).Where(sq.Eq{"a.slug": slug}).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build select query")
+	}
+
+	var a sqliteArticle
+	err = s.db.QueryRowxContext(ctx, query, args...).StructScan(&a)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to query article")
+	}
+
+	return a.toArticle(), nil
+}
+
+// CreateArticle adds new article with its tags and sets its id and version
+func (s *Store) CreateArticle(ctx context.Context, a *app.Article) error {
+	query, args, err :=
+		sq.Insert("articles").
+			Columns("slug", "title", "description", "body", "author_id", "created", "updated").
+			Values(a.Slug, a.Title, a.Description, a.Body, a.Author.Id, timestamp(a.Created), timestamp(a.Updated)).
+			Suffix("RETURNING id, version").
+			ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build insert query")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowxContext(ctx, query, args...).Scan(&a.Id, &a.Version)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute insert query")
+	}
+
+	err = setArticleTags(ctx, tx, a.Id, a.TagList)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// DeleteArticle deletes article with its tags, comments and favorites
+func (s *Store) DeleteArticle(ctx context.Context, id int) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM articles WHERE id = ?`, id)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute delete query")
+	}
+
+	return nil
+}
+
+// UpdateArticle modifies non-empty fields and replaces tags of the article
+// only if its version matches a.Version. It returns
+// app.ErrorArticleVersionMismatch otherwise.
+func (s *Store) UpdateArticle(ctx context.Context, a *app.Article) error {
+	fields := a.UpdateMap()
+	if updated, ok := fields["updated"].(time.Time); ok {
+		fields["updated"] = timestamp(updated)
+	}
+
+	query, args, err :=
+		sq.Update("articles").
+			SetMap(fields).
+			Set("version", sq.Expr("version + 1")).
+			Where(sq.Eq{"id": a.Id, "version": a.Version}).
+			ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build update query")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute update query")
+	}
+
+	// Nothing is updated when article was modified after it was read
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get number of updated rows")
+	}
+	if n == 0 {
+		return app.ErrorArticleVersionMismatch
+	}
+	a.Version++
+
+	err = setArticleTags(ctx, tx, a.Id, a.TagList)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// setArticleTags replaces tags of the article with the given list. Tags that
+// don't exist yet are created.
+func setArticleTags(ctx context.Context, tx *sqlx.Tx, articleId int, tags []string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM article_tags WHERE article_id = ?`, articleId)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete article tags")
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	insert := sq.Insert("tags").Columns("name").Suffix("ON CONFLICT (name) DO NOTHING")
+	for _, tag := range tags {
+		insert = insert.Values(tag)
+	}
+
+	query, args, err := insert.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build tags insert query")
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed to insert tags")
+	}
+
+	query, args, err =
+		sq.Insert("article_tags").
+			Columns("article_id", "tag_id").
+			Select(sq.Select("?", "id").From("tags").Where(sq.Eq{"name": tags})).
+			ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build article tags insert query")
+	}
+
+	_, err = tx.ExecContext(ctx, query, append([]interface{}{articleId}, args...)...)
+	if err != nil {
+		return errors.Wrap(err, "failed to insert article tags")
+	}
+
+	return nil
+}
+
+// FavoriteArticle adds article to the user favorites. Favoriting twice is not
+// an error.
+func (s *Store) FavoriteArticle(ctx context.Context, user *app.Profile, a *app.Article) error {
+	query := `
+		INSERT INTO favorites (user_id, article_id)
+		VALUES (?, ?)
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err := s.db.ExecContext(ctx, query, user.Id, a.Id)
+	if err != nil {
+		return errors.Wrap(err, "failed to add favorite to db")
+	}
+
+	return nil
+}
+
+// UnfavoriteArticle removes article from the user favorites if it's there
+func (s *Store) UnfavoriteArticle(ctx context.Context, user *app.Profile, a *app.Article) error {
+	query := `
+		DELETE FROM favorites
+		WHERE user_id = ? AND article_id = ?
+	`
+
+	_, err := s.db.ExecContext(ctx, query, user.Id, a.Id)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete favorite from db")
+	}
+
+	return nil
+}
+
+// ListTags returns names of all tags that are used by at least one article
+func (s *Store) ListTags(ctx context.Context) ([]string, error) {
+	query := `
+		SELECT DISTINCT t.name
+		FROM tags t
+		JOIN article_tags at ON (at.tag_id = t.id)
+		ORDER BY t.name
+	`
+
+	tags := []string{}
+	err := s.db.SelectContext(ctx, &tags, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query tags")
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,154 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+)
+
+// sqliteComment is the same as app.Comment but expands Author type to its
+// fields and uses sql.Null* types to allow scanning query result.
+type sqliteComment struct {
+	Id          int
+	ArticleId   int
+	Body        string
+	Created     time.Time
+	Updated     time.Time
+	AuthorId    int
+	AuthorName  string
+	AuthorBio   sql.NullString
+	AuthorImage sql.NullString
+	Following   bool
+}
+
+func (c sqliteComment) toComment() *app.Comment {
+	return &app.Comment{
+		Id:        c.Id,
+		ArticleId: c.ArticleId,
+		Body:      c.Body,
+		Created:   c.Created,
+		Updated:   c.Updated,
+		Author: app.Profile{
+			Id:        c.AuthorId,
+			Name:      c.AuthorName,
+			Bio:       c.AuthorBio.String,
+			Image:     c.AuthorImage.String,
+			Following: c.Following,
+		},
+	}
+}
+
+// CreateComment adds new comment and sets its id
+func (s *Store) CreateComment(ctx context.Context, c *app.Comment) error {
+	query := `
+		INSERT INTO comments (article_id, author_id, body, created, updated)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	err := s.db.
+		QueryRowxContext(ctx, query, c.ArticleId, c.Author.Id, c.Body, timestamp(c.Created), timestamp(c.Updated)).
+		Scan(&c.Id)
+	if err != nil {
+		return errors.Wrap(err, "failed to insert comment to db")
+	}
+
+	return nil
+}
+
+// GetComment returns comment by id. Following field of the author is not set.
+func (s *Store) GetComment(ctx context.Context, id int) (*app.Comment, error) {
+	query := `
+		SELECT
+			c.id as id,
+			c.article_id as article_id,
+			c.body as body,
+			c.created as created,
+			c.updated as updated,
+			c.author_id as author_id,
+			u.name as author_name,
+			u.bio as author_bio,
+			u.image as author_image
+		FROM comments c
+		JOIN users u ON (c.author_id = u.id)
+		WHERE c.id = ?
+	`
+
+	var c sqliteComment
+	err := s.db.QueryRowxContext(ctx, query, id).StructScan(&c)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to query comment")
+	}
+
+	return c.toComment(), nil
+}
+
+// ListComments returns comments of the article sorted from the oldest to the
+// newest. Viewer is optional, when it's set the following field of comment
+// authors is filled for it.
+func (s *Store) ListComments(ctx context.Context, articleId int, viewer *app.Profile) ([]*app.Comment, error) {
+	query := `
+		SELECT
+			c.id as id,
+			c.article_id as article_id,
+			c.body as body,
+			c.created as created,
+			c.updated as updated,
+			c.author_id as author_id,
+			u.name as author_name,
+			u.bio as author_bio,
+			u.image as author_image,
+			f.followee IS NOT NULL as following
+		FROM comments c
+		JOIN users u ON (c.author_id = u.id)
+		LEFT JOIN followers f ON (u.id = f.followee AND f.follower = ?)
+		WHERE c.article_id = ?
+		ORDER BY c.created, c.id
+	`
+
+	// If viewer is not set then id will be 0 and "following" will always be
+	// false because ids start with 1.
+	viewerId := 0
+	if viewer != nil {
+		viewerId = viewer.Id
+	}
+
+	rows, err := s.db.QueryxContext(ctx, query, viewerId, articleId)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query comments")
+	}
+	defer rows.Close()
+
+	comments := []*app.Comment{}
+	for rows.Next() {
+		var c sqliteComment
+		err := rows.StructScan(&c)
+		if err != nil {
+			return nil, errors.Wrap(err, "row scan failed")
+		}
+
+		comments = append(comments, c.toComment())
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows scan error")
+	}
+
+	return comments, nil
+}
+
+// DeleteComment deletes comment by id
+func (s *Store) DeleteComment(ctx context.Context, id int) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM comments WHERE id = ?`, id)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete comment from db")
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package sqlite
+
+import (
+	"context"
+	"embed"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/db"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// Migrations returns embedded migrations sorted by version
+func Migrations() ([]db.Migration, error) {
+	return db.LoadMigrations(migrationsFS, "migrations")
+}
+
+// Migrate applies pending migrations, each in its own transaction. Unlike
+// postgres there is no lock around the whole run, so it must not be called by
+// several processes at once.
+func (s *Store) Migrate(ctx context.Context) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+
+	applied, err := s.appliedMigrations(ctx, migrations)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		err = s.applyMigration(ctx, m.Up, func(tx *sqlx.Tx) error {
+			_, err := tx.ExecContext(ctx,
+				`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+				m.Version, m.Name, timestamp(time.Now()),
+			)
+			return err
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to apply migration %d_%s", m.Version, m.Name)
+		}
+	}
+
+	return nil
+}
+
+// MigrateDown reverts n last applied migrations
+func (s *Store) MigrateDown(ctx context.Context, n int) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+
+	applied, err := s.appliedMigrations(ctx, migrations)
+	if err != nil {
+		return err
+	}
+
+	for i := len(migrations) - 1; i >= 0 && n > 0; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+
+		if m.Down == "" {
+			return errors.Errorf("migration %d_%s has no down file", m.Version, m.Name)
+		}
+
+		err = s.applyMigration(ctx, m.Down, func(tx *sqlx.Tx) error {
+			_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, m.Version)
+			return err
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to revert migration %d_%s", m.Version, m.Name)
+		}
+
+		n--
+	}
+
+	return nil
+}
+
+// MigrationStatus returns all of the embedded migrations with the time they
+// were applied
+func (s *Store) MigrationStatus(ctx context.Context) ([]db.MigrationStatus, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := s.appliedMigrations(ctx, migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.Statuses(migrations, applied), nil
+}
+
+// appliedMigrations creates migrations table if needed and returns applied
+// migration versions with the time they were applied. It fails if database
+// has migrations unknown to this binary, i.e. the binary is older than the
+// schema.
+func (s *Store) appliedMigrations(ctx context.Context, migrations []db.Migration) (map[int]time.Time, error) {
+	_, err := s.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version integer PRIMARY KEY,
+			name text NOT NULL,
+			applied_at timestamp NOT NULL
+		)
+	`)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create migrations table")
+	}
+
+	rows, err := s.db.QueryxContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query applied migrations")
+	}
+	defer rows.Close()
+
+	known := make(map[int]bool, len(migrations))
+	for _, m := range migrations {
+		known[m.Version] = true
+	}
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var t time.Time
+		err = rows.Scan(&version, &t)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan applied migration")
+		}
+
+		if !known[version] {
+			return nil, errors.Errorf("unknown migration %d is applied, database schema is newer than the binary", version)
+		}
+
+		applied[version] = t
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to query applied migrations")
+	}
+
+	return applied, nil
+}
+
+// applyMigration executes migration query and records the change with record
+// in the same transaction
+func (s *Store) applyMigration(ctx context.Context, query string, record func(tx *sqlx.Tx) error) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	err = record(tx)
+	if err != nil {
+		return errors.Wrap(err, "failed to record migration")
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS revoked_tokens;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS favorites;
+DROP TABLE IF EXISTS article_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS followers;
+DROP TABLE IF EXISTS articles;
+DROP TABLE IF EXISTS users;
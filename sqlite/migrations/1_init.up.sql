@@ -0,0 +1,79 @@
+-- Timestamps are stored as text in UTC with fixed width fractional seconds,
+-- so they are compared and sorted as strings.
+
+CREATE TABLE IF NOT EXISTS users (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    email text UNIQUE NOT NULL,
+    name text UNIQUE NOT NULL,
+    bio text,
+    image text, -- base64
+    password_hash text NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS articles (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    slug text UNIQUE NOT NULL,
+    title text NOT NULL,
+    description text,
+    body text,
+    created timestamp NOT NULL,
+    updated timestamp NOT NULL,
+    author_id integer NOT NULL REFERENCES users (id),
+    version integer NOT NULL DEFAULT 1
+);
+
+CREATE INDEX articles_created_idx ON articles (created, id);
+
+CREATE TABLE IF NOT EXISTS followers (
+    follower integer REFERENCES users (id),
+    followee integer REFERENCES users (id),
+    UNIQUE(follower, followee)
+);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text UNIQUE NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS article_tags (
+    article_id integer NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    tag_id integer NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    UNIQUE(article_id, tag_id)
+);
+
+CREATE INDEX article_tags_tag_id_idx ON article_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS favorites (
+    user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    article_id integer NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    UNIQUE(user_id, article_id)
+);
+
+CREATE INDEX favorites_article_id_idx ON favorites (article_id);
+
+CREATE TABLE IF NOT EXISTS comments (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    article_id integer NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    author_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    body text NOT NULL,
+    created timestamp NOT NULL,
+    updated timestamp NOT NULL
+);
+
+CREATE INDEX comments_article_id_idx ON comments (article_id);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    hash text PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created timestamp NOT NULL,
+    expires timestamp NOT NULL
+);
+
+CREATE INDEX refresh_tokens_user_id_idx ON refresh_tokens (user_id);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    id text PRIMARY KEY,
+    expires timestamp NOT NULL
+);
+
+CREATE INDEX revoked_tokens_expires_idx ON revoked_tokens (expires);
@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+)
+
+// sqliteProfile is the same as app.Profile but suitable for StructScan
+// method because it has sql.Null* types.
+type sqliteProfile struct {
+	Id        int
+	Name      string
+	Bio       sql.NullString
+	Image     sql.NullString
+	Following bool
+}
+
+// GetProfile returns profile by username or app.ErrorProfileNotFound.
+// Follower is optional, when it's set the following field is filled for it.
+func (s *Store) GetProfile(ctx context.Context, username string, follower *app.Profile) (*app.Profile, error) {
+	query := `
+		SELECT
+			id,
+			name,
+			bio,
+			image,
+			followee IS NOT NULL AS following
+		FROM users u
+		LEFT JOIN followers f
+		ON (u.id = f.followee AND f.follower = ?)
+		WHERE u.name = ?
+	`
+
+	// If follower is not set then id will be 0 and "following" will always be
+	// false because ids start with 1.
+	followerId := 0
+	if follower != nil {
+		followerId = follower.Id
+	}
+
+	var p sqliteProfile
+	err := s.db.QueryRowxContext(ctx, query, followerId, username).StructScan(&p)
+	if err == sql.ErrNoRows {
+		return nil, app.ErrorProfileNotFound
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to query profile")
+	}
+
+	profile := app.Profile{
+		Id:        p.Id,
+		Name:      p.Name,
+		Bio:       p.Bio.String,
+		Image:     p.Image.String,
+		Following: p.Following,
+	}
+
+	return &profile, nil
+}
+
+// FollowProfile makes follower follow followee. Following twice is not an
+// error.
+func (s *Store) FollowProfile(ctx context.Context, follower, followee *app.Profile) error {
+	query := `
+		INSERT INTO followers (follower, followee)
+		VALUES (?, ?)
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err := s.db.ExecContext(ctx, query, follower.Id, followee.Id)
+	if err != nil {
+		return errors.Wrap(err, "failed to add follow relationship to db")
+	}
+
+	return nil
+}
+
+// UnfollowProfile makes follower stop following followee
+func (s *Store) UnfollowProfile(ctx context.Context, follower, followee *app.Profile) error {
+	query := `
+		DELETE FROM followers
+		WHERE follower = ? AND followee = ?
+	`
+
+	_, err := s.db.ExecContext(ctx, query, follower.Id, followee.Id)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete follow relationship from db")
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+// Package sqlite implements the app stores on top of SQLite. It's meant for
+// local development and small self-hosted deployments where running Postgres
+// is a burden.
+//
+// Store follows the semantics of the postgres store. Full-text search is
+// approximated with the search package because SQLite FTS doesn't support
+// ranking by article fields the way Postgres does.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/db"
+)
+
+// timeFormat is the format timestamps are stored in. Fractional seconds have
+// fixed width so timestamps in UTC are sorted correctly as strings.
+const timeFormat = "2006-01-02 15:04:05.000000000-07:00"
+
+type Store struct {
+	db *sqlx.DB
+}
+
+// NewStore opens SQLite database at DSN, e.g. file path or ":memory:".
+// Foreign keys are enabled unless DSN sets them explicitly.
+func NewStore(DSN string) (*Store, error) {
+	if !strings.Contains(DSN, "_foreign_keys=") && !strings.Contains(DSN, "_fk=") {
+		sep := "?"
+		if strings.Contains(DSN, "?") {
+			sep = "&"
+		}
+		DSN += sep + "_foreign_keys=1"
+	}
+
+	conn, err := sqlx.Connect("sqlite3", DSN)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open sqlite db")
+	}
+
+	// SQLite allows only one writer at a time, so concurrent writes would fail
+	// with "database is locked". A single connection also keeps in-memory
+	// database alive.
+	conn.SetMaxOpenConns(1)
+	conn.SetConnMaxLifetime(0)
+
+	conn.MapperFunc(db.CamelToSnakeASCII)
+
+	return &Store{db: conn}, nil
+}
+
+// Ping checks that database is reachable
+func (s *Store) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// Stats returns connection pool statistics
+func (s *Store) Stats() sql.DBStats {
+	return s.db.Stats()
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// timestamp returns t in the format it's stored in
+func timestamp(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
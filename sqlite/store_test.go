@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dzeban/conduit/app"
+	"github.com/dzeban/conduit/storetest"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	s, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	if err := s.Migrate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Store {
+		return newTestStore(t)
+	})
+}
+
+func TestRefreshToken(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	u := &app.User{Name: "alice", Email: "alice@example.com", PasswordHash: "hash"}
+	if err := s.AddUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	token := app.RefreshToken{Hash: "hash", UserId: u.Id, Created: created, Expires: created.Add(time.Hour)}
+	if err := s.AddRefreshToken(ctx, &token); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.DeleteRefreshToken(ctx, token.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got == nil || got.UserId != u.Id || !got.Created.Equal(token.Created) || !got.Expires.Equal(token.Expires) {
+		t.Errorf("invalid deleted token, expected %+v, got %+v", token, got)
+	}
+
+	// Token can be used only once
+	if got, err := s.DeleteRefreshToken(ctx, token.Hash); got != nil || err != nil {
+		t.Errorf("expected nil for deleted token, got %+v, %v", got, err)
+	}
+}
+
+func TestMigrateDown(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	if err := s.MigrateDown(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := s.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, status := range statuses {
+		if status.Applied != nil {
+			t.Errorf("migration %d is applied after down", status.Version)
+		}
+	}
+
+	// Migrations are applied again from scratch
+	if err := s.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+)
+
+// AddRefreshToken stores refresh token hash
+func (s *Store) AddRefreshToken(ctx context.Context, t *app.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (hash, user_id, created, expires)
+		VALUES (?, ?, ?, ?)
+	`
+
+	_, err := s.db.ExecContext(ctx, query, t.Hash, t.UserId, timestamp(t.Created), timestamp(t.Expires))
+	if err != nil {
+		return errors.Wrap(err, "failed to insert refresh token")
+	}
+
+	return nil
+}
+
+// DeleteRefreshToken deletes refresh token by hash and returns it. Token is
+// deleted and returned in a single query so it can't be used twice by
+// concurrent requests. It returns nil if token doesn't exist.
+func (s *Store) DeleteRefreshToken(ctx context.Context, hash string) (*app.RefreshToken, error) {
+	query := `
+		DELETE FROM refresh_tokens
+		WHERE hash = ?
+		RETURNING hash, user_id, created, expires
+	`
+
+	var t app.RefreshToken
+	err := s.db.QueryRowxContext(ctx, query, hash).Scan(&t.Hash, &t.UserId, &t.Created, &t.Expires)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to delete refresh token")
+	}
+
+	return &t, nil
+}
+
+// DeleteUserRefreshToken deletes refresh token by hash only if it's issued to
+// the user with id and returns it. It returns nil if there is no such token.
+func (s *Store) DeleteUserRefreshToken(ctx context.Context, userId int, hash string) (*app.RefreshToken, error) {
+	query := `
+		DELETE FROM refresh_tokens
+		WHERE hash = ? AND user_id = ?
+		RETURNING hash, user_id, created, expires
+	`
+
+	var t app.RefreshToken
+	err := s.db.QueryRowxContext(ctx, query, hash, userId).Scan(&t.Hash, &t.UserId, &t.Created, &t.Expires)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to delete refresh token")
+	}
+
+	return &t, nil
+}
+
+// RevokeToken adds token id to the revoked tokens. Revoked tokens that are
+// already expired are deleted because they are rejected anyway.
+func (s *Store) RevokeToken(ctx context.Context, id string, expires time.Time) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM revoked_tokens WHERE expires < ?`, timestamp(time.Now()))
+	if err != nil {
+		return errors.Wrap(err, "failed to delete expired revoked tokens")
+	}
+
+	query := `
+		INSERT INTO revoked_tokens (id, expires)
+		VALUES (?, ?)
+		ON CONFLICT (id) DO NOTHING
+	`
+	_, err = tx.ExecContext(ctx, query, id, timestamp(expires))
+	if err != nil {
+		return errors.Wrap(err, "failed to insert revoked token")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// IsTokenRevoked checks if token id is revoked
+func (s *Store) IsTokenRevoked(ctx context.Context, id string) (bool, error) {
+	var revoked bool
+	err := s.db.QueryRowxContext(ctx, `SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE id = ?)`, id).Scan(&revoked)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to query revoked token")
+	}
+
+	return revoked, nil
+}
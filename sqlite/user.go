@@ -0,0 +1,121 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+)
+
+// GetUser returns user by email or nil if there is no such user
+func (s *Store) GetUser(ctx context.Context, email string) (*app.User, error) {
+	query := `
+		SELECT
+			id,
+			name,
+			bio,
+			image,
+			password_hash
+		FROM
+			users
+		WHERE
+			email = ?
+	`
+
+	var id int
+	var name, passwordHash string
+	var bio, image sql.NullString
+	err := s.db.QueryRowxContext(ctx, query, email).Scan(&id, &name, &bio, &image, &passwordHash)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to query user")
+	}
+
+	user := app.User{
+		Id:           id,
+		Name:         name,
+		Email:        email,
+		PasswordHash: passwordHash,
+		Bio:          bio.String,
+		Image:        image.String,
+	}
+
+	return &user, nil
+}
+
+// GetUserById returns user by id or nil if there is no such user
+func (s *Store) GetUserById(ctx context.Context, id int) (*app.User, error) {
+	query := `
+		SELECT
+			name,
+			email,
+			bio,
+			image,
+			password_hash
+		FROM
+			users
+		WHERE
+			id = ?
+	`
+
+	var name, email, passwordHash string
+	var bio, image sql.NullString
+	err := s.db.QueryRowxContext(ctx, query, id).Scan(&name, &email, &bio, &image, &passwordHash)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to query user")
+	}
+
+	user := app.User{
+		Id:           id,
+		Name:         name,
+		Email:        email,
+		PasswordHash: passwordHash,
+		Bio:          bio.String,
+		Image:        image.String,
+	}
+
+	return &user, nil
+}
+
+// AddUser adds new user and sets its id
+func (s *Store) AddUser(ctx context.Context, user *app.User) error {
+	query := `
+		INSERT INTO users (name, email, password_hash, bio, image)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	err := s.db.
+		QueryRowxContext(ctx, query, user.Name, user.Email, user.PasswordHash, user.Bio, user.Image).
+		Scan(&user.Id)
+	if err != nil {
+		return errors.Wrap(err, "failed to insert user to db")
+	}
+
+	return nil
+}
+
+// UpdateUser modifies non-empty fields of the user by id
+func (s *Store) UpdateUser(ctx context.Context, user *app.User) error {
+	query, args, err :=
+		sq.Update("users").
+			SetMap(user.UpdateMap()).
+			Where(sq.Eq{"id": user.Id}).
+			ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build update query")
+	}
+
+	_, err = s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute update query")
+	}
+
+	return nil
+}
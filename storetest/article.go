@@ -0,0 +1,487 @@
+package storetest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dzeban/conduit/app"
+)
+
+func testArticles(t *testing.T, f *fixture) {
+	alice := f.addUser("alice")
+	a := f.addArticle(alice, "Title", "b", "a", "b")
+
+	if a.Id == 0 || a.Version != 1 {
+		t.Errorf("id and version are not set on create: %+v", a)
+	}
+
+	got := f.getArticle(a.Slug, nil)
+
+	if got.Id != a.Id || got.Title != a.Title || got.Description != a.Description || got.Body != a.Body {
+		t.Errorf("invalid article, expected %+v, got %+v", a, got)
+	}
+
+	if !got.Created.Equal(a.Created) || !got.Updated.Equal(a.Updated) {
+		t.Errorf("invalid article times, expected %v and %v, got %v and %v", a.Created, a.Updated, got.Created, got.Updated)
+	}
+
+	if got.Author.Id != alice.Id || got.Author.Name != alice.Name {
+		t.Errorf("invalid article author: %+v", got.Author)
+	}
+
+	// Tags are sorted and deduplicated
+	if !equalStrings(got.TagList, []string{"a", "b"}) {
+		t.Errorf("invalid tags: %v", got.TagList)
+	}
+
+	if got.Version != 1 || got.Favorited || got.FavoritesCount != 0 {
+		t.Errorf("invalid article state: %+v", got)
+	}
+
+	if got, err := f.s.GetArticle(f.ctx, "unknown", nil); got != nil || err != nil {
+		t.Errorf("expected nil for unknown slug, got %+v, %v", got, err)
+	}
+
+	// Slugs are unique
+	dup := *a
+	dup.Id = 0
+	if err := f.s.CreateArticle(f.ctx, &dup); err == nil {
+		t.Errorf("article with duplicate slug is created")
+	}
+
+	noTags := f.addArticle(alice, "No tags")
+	if got := f.getArticle(noTags.Slug, nil); len(got.TagList) != 0 {
+		t.Errorf("article without tags has tags: %v", got.TagList)
+	}
+}
+
+func testUpdateArticle(t *testing.T, f *fixture) {
+	alice := f.addUser("alice")
+	original := f.addArticle(alice, "Title", "old", "common")
+
+	a := *original
+	a.Title = "New title"
+	a.Description = ""
+	a.Body = ""
+	a.TagList = []string{"new", "common"}
+	if err := f.s.UpdateArticle(f.ctx, &a); err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Version != 2 {
+		t.Errorf("invalid version after update, expected 2, got %d", a.Version)
+	}
+
+	// Stale version is rejected
+	stale := *original
+	stale.Title = "Stale title"
+	if err := f.s.UpdateArticle(f.ctx, &stale); err != app.ErrorArticleVersionMismatch {
+		t.Errorf("expected version mismatch, got %v", err)
+	}
+
+	got := f.getArticle(a.Slug, nil)
+
+	// Empty fields are not updated
+	if got.Title != "New title" || got.Description != original.Description || got.Body != original.Body {
+		t.Errorf("invalid article after update: %+v", got)
+	}
+
+	if got.Version != 2 {
+		t.Errorf("invalid stored version, expected 2, got %d", got.Version)
+	}
+
+	if !equalStrings(got.TagList, []string{"common", "new"}) {
+		t.Errorf("invalid tags after update: %v", got.TagList)
+	}
+
+	// Update of missing article is the same as of modified one
+	missing := a
+	missing.Id += 100
+	if err := f.s.UpdateArticle(f.ctx, &missing); err != app.ErrorArticleVersionMismatch {
+		t.Errorf("expected version mismatch for missing article, got %v", err)
+	}
+}
+
+func testDeleteArticle(t *testing.T, f *fixture) {
+	alice := f.addUser("alice")
+	bob := app.ProfileFromUser(f.addUser("bob"))
+	a := f.addArticle(alice, "Deleted", "deleted")
+	kept := f.addArticle(alice, "Kept", "kept")
+
+	if err := f.s.FavoriteArticle(f.ctx, bob, a); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &app.Comment{ArticleId: a.Id, Author: *bob, Body: "Comment"}
+	if err := f.s.CreateComment(f.ctx, c); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.s.DeleteArticle(f.ctx, a.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := f.s.GetComment(f.ctx, c.Id); got != nil || err != nil {
+		t.Errorf("expected nil for comment of deleted article, got %+v, %v", got, err)
+	}
+
+	tags, err := f.s.ListTags(f.ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !equalStrings(tags, []string{"kept"}) {
+		t.Errorf("invalid tags after delete, expected [kept], got %v", tags)
+	}
+
+	if got, err := f.s.GetArticle(f.ctx, a.Slug, nil); got != nil || err != nil {
+		t.Errorf("expected nil for deleted article, got %+v, %v", got, err)
+	}
+
+	filter := app.NewArticleListFilter()
+	if got := f.list(filter); !equalStrings(got, slugs(kept)) {
+		t.Errorf("invalid list after delete, got %v", got)
+	}
+
+	filter.Favorited = bob
+	if n := f.count(filter); n != 0 {
+		t.Errorf("favorites of deleted article are not deleted")
+	}
+
+	// Deleting missing article is not an error
+	if err := f.s.DeleteArticle(f.ctx, a.Id); err != nil {
+		t.Errorf("failed to delete missing article: %v", err)
+	}
+}
+
+func testListArticles(t *testing.T, f *fixture) {
+	alice := f.addUser("alice")
+	bob := f.addUser("bob")
+
+	a1 := f.addArticle(alice, "First", "go")
+	b1 := f.addArticle(bob, "Second", "go", "rust")
+	a2 := f.addArticle(alice, "Third", "rust")
+	b2 := f.addArticle(bob, "Fourth")
+
+	if err := f.s.FavoriteArticle(f.ctx, app.ProfileFromUser(alice), b1); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		filter   func(filter *app.ArticleListFilter)
+		expected []string
+		count    int
+	}{
+		{
+			"All",
+			func(filter *app.ArticleListFilter) {},
+			slugs(b2, a2, b1, a1),
+			4,
+		},
+		{
+			"Author",
+			func(filter *app.ArticleListFilter) { filter.Author = app.ProfileFromUser(alice) },
+			slugs(a2, a1),
+			2,
+		},
+		{
+			"Tag",
+			func(filter *app.ArticleListFilter) { filter.Tag = "rust" },
+			slugs(a2, b1),
+			2,
+		},
+		{
+			"UnknownTag",
+			func(filter *app.ArticleListFilter) { filter.Tag = "unknown" },
+			slugs(),
+			0,
+		},
+		{
+			"Favorited",
+			func(filter *app.ArticleListFilter) { filter.Favorited = app.ProfileFromUser(alice) },
+			slugs(b1),
+			1,
+		},
+		{
+			"Limit",
+			func(filter *app.ArticleListFilter) { filter.Limit = 2 },
+			slugs(b2, a2),
+			4,
+		},
+		{
+			"Offset",
+			func(filter *app.ArticleListFilter) { filter.Limit, filter.Offset = 2, 1 },
+			slugs(a2, b1),
+			4,
+		},
+		{
+			"OffsetPastEnd",
+			func(filter *app.ArticleListFilter) { filter.Offset = 10 },
+			slugs(),
+			4,
+		},
+		{
+			"Cursor",
+			func(filter *app.ArticleListFilter) { filter.After = app.NewArticleCursor(a2) },
+			slugs(b1, a1),
+			4,
+		},
+		{
+			"CursorWithFilter",
+			func(filter *app.ArticleListFilter) {
+				filter.Author = app.ProfileFromUser(bob)
+				filter.After = app.NewArticleCursor(b2)
+				filter.Limit = 1
+			},
+			slugs(b1),
+			2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := app.NewArticleListFilter()
+			tt.filter(&filter)
+
+			if got := f.list(filter); !equalStrings(got, tt.expected) {
+				t.Errorf("invalid list, expected %v, got %v", tt.expected, got)
+			}
+
+			if n := f.count(filter); n != tt.count {
+				t.Errorf("invalid count, expected %d, got %d", tt.count, n)
+			}
+		})
+	}
+}
+
+// testListOrder checks that articles created at the same time are sorted by id
+// in descending order, so pages don't overlap or skip articles
+func testListOrder(t *testing.T, f *fixture) {
+	alice := f.addUser("alice")
+	first := f.addArticle(alice, "First")
+
+	tie := &app.Article{
+		Slug:    "tie",
+		Title:   "Tie",
+		Author:  *app.ProfileFromUser(alice),
+		Created: first.Created,
+		Updated: first.Updated,
+	}
+	if err := f.s.CreateArticle(f.ctx, tie); err != nil {
+		t.Fatal(err)
+	}
+
+	last := f.addArticle(alice, "Last")
+
+	filter := app.NewArticleListFilter()
+	expected := slugs(last, tie, first)
+	if got := f.list(filter); !equalStrings(got, expected) {
+		t.Fatalf("invalid order, expected %v, got %v", expected, got)
+	}
+
+	// Walk the list page by page with cursor
+	var got []string
+	filter.Limit = 1
+	for page := 0; page <= len(expected); page++ {
+		articles, err := f.s.ListArticles(f.ctx, &filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(articles) == 0 {
+			break
+		}
+
+		got = append(got, articles[0].Slug)
+		filter.After = app.NewArticleCursor(articles[0])
+	}
+
+	if !equalStrings(got, expected) {
+		t.Errorf("invalid pages, expected %v, got %v", expected, got)
+	}
+}
+
+func testFeed(t *testing.T, f *fixture) {
+	alice := f.addUser("alice")
+	bob := f.addUser("bob")
+	carol := f.addUser("carol")
+	reader := f.addUser("reader")
+
+	a1 := f.addArticle(alice, "First")
+	f.addArticle(bob, "Second")
+	c1 := f.addArticle(carol, "Third")
+	a2 := f.addArticle(alice, "Fourth")
+
+	filter := app.NewArticleListFilter()
+	filter.FeedOf = reader
+	filter.CurrentUser = reader
+
+	if got := f.list(filter); len(got) != 0 {
+		t.Fatalf("feed without follows is not empty: %v", got)
+	}
+
+	for _, u := range []*app.User{alice, carol} {
+		err := f.s.FollowProfile(f.ctx, app.ProfileFromUser(reader), app.ProfileFromUser(u))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := slugs(a2, c1, a1)
+	if got := f.list(filter); !equalStrings(got, expected) {
+		t.Errorf("invalid feed, expected %v, got %v", expected, got)
+	}
+
+	if n := f.count(filter); n != 3 {
+		t.Errorf("invalid feed count, expected 3, got %d", n)
+	}
+
+	articles, err := f.s.ListArticles(f.ctx, &filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, a := range articles {
+		if !a.Author.Following {
+			t.Errorf("author of %s in feed is not followed", a.Slug)
+		}
+	}
+
+	if got := f.getArticle(a1.Slug, app.ProfileFromUser(reader)); !got.Author.Following {
+		t.Errorf("author of followed article is not followed")
+	}
+
+	if got := f.getArticle(a1.Slug, nil); got.Author.Following {
+		t.Errorf("author is followed by anonymous viewer")
+	}
+
+	err = f.s.UnfollowProfile(f.ctx, app.ProfileFromUser(reader), app.ProfileFromUser(alice))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := f.list(filter); !equalStrings(got, slugs(c1)) {
+		t.Errorf("invalid feed after unfollow, expected %v, got %v", slugs(c1), got)
+	}
+
+	// Feed of other user is not affected
+	filter.FeedOf = bob
+	if got := f.list(filter); len(got) != 0 {
+		t.Errorf("feed of user without follows is not empty: %v", got)
+	}
+}
+
+func testFavorites(t *testing.T, f *fixture) {
+	alice := f.addUser("alice")
+	bob := app.ProfileFromUser(f.addUser("bob"))
+	carol := app.ProfileFromUser(f.addUser("carol"))
+	a := f.addArticle(alice, "Title")
+
+	// Favoriting twice is fine
+	for _, p := range []*app.Profile{bob, bob, carol} {
+		if err := f.s.FavoriteArticle(f.ctx, p, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := f.getArticle(a.Slug, bob)
+	if !got.Favorited || got.FavoritesCount != 2 {
+		t.Errorf("invalid favorites of favorited article: %+v", got)
+	}
+
+	got = f.getArticle(a.Slug, nil)
+	if got.Favorited || got.FavoritesCount != 2 {
+		t.Errorf("invalid favorites for anonymous viewer: %+v", got)
+	}
+
+	// Unfavoriting twice is fine too
+	for i := 0; i < 2; i++ {
+		if err := f.s.UnfavoriteArticle(f.ctx, bob, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got = f.getArticle(a.Slug, bob)
+	if got.Favorited || got.FavoritesCount != 1 {
+		t.Errorf("invalid favorites of unfavorited article: %+v", got)
+	}
+
+	filter := app.NewArticleListFilter()
+	filter.CurrentUser = &app.User{Id: carol.Id, Name: carol.Name}
+	articles, err := f.s.ListArticles(f.ctx, &filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(articles) != 1 || !articles[0].Favorited || articles[0].FavoritesCount != 1 {
+		t.Errorf("invalid favorites in list: %+v", articles)
+	}
+}
+
+func testSearch(t *testing.T, f *fixture) {
+	alice := f.addUser("alice")
+
+	inTitle := f.addArticle(alice, "Gopher")
+	f.addArticle(alice, "Unrelated")
+
+	inBody := f.addArticle(alice, "Another")
+	inBody.Body = "Story about gopher"
+	inBody.TagList = nil
+	if err := f.s.UpdateArticle(f.ctx, inBody); err != nil {
+		t.Fatal(err)
+	}
+
+	filter := app.NewArticleListFilter()
+	filter.Query = "gopher"
+
+	expected := slugs(inTitle, inBody)
+	if got := f.list(filter); !equalStrings(got, expected) {
+		t.Errorf("invalid search result, expected %v, got %v", expected, got)
+	}
+
+	if n := f.count(filter); n != 2 {
+		t.Errorf("invalid search count, expected 2, got %d", n)
+	}
+
+	articles, err := f.s.ListArticles(f.ctx, &filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, a := range articles {
+		if a.Snippet == "" {
+			t.Errorf("search result %s has no snippet", a.Slug)
+		}
+	}
+
+	// All of the words must match
+	filter.Query = "gopher story"
+	if got := f.list(filter); !equalStrings(got, slugs(inBody)) {
+		t.Errorf("invalid search result for all words, got %v", got)
+	}
+
+	// Snippet is HTML, markup from the body is escaped
+	script := f.addArticle(alice, "Script")
+	script.Body = `<script>alert("xss")</script>`
+	script.TagList = nil
+	if err := f.s.UpdateArticle(f.ctx, script); err != nil {
+		t.Fatal(err)
+	}
+
+	filter.Query = "alert"
+	articles, err = f.s.ListArticles(f.ctx, &filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(articles) != 1 {
+		t.Fatalf("invalid search result for script, got %d articles", len(articles))
+	}
+
+	snippet := articles[0].Snippet
+	if strings.Contains(snippet, "<script") || !strings.Contains(snippet, "&lt;script&gt;") ||
+		!strings.Contains(snippet, "<b>alert</b>") {
+		t.Errorf("snippet is not escaped: %q", snippet)
+	}
+}
@@ -0,0 +1,63 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/dzeban/conduit/app"
+)
+
+func testProfiles(t *testing.T, f *fixture) {
+	alice := app.ProfileFromUser(f.addUser("alice"))
+	bob := app.ProfileFromUser(f.addUser("bob"))
+
+	following := func(viewer *app.Profile) bool {
+		t.Helper()
+
+		p, err := f.s.GetProfile(f.ctx, "alice", viewer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if p.Id != alice.Id || p.Name != alice.Name || p.Bio != alice.Bio {
+			t.Fatalf("invalid profile: %+v", p)
+		}
+
+		return p.Following
+	}
+
+	if _, err := f.s.GetProfile(f.ctx, "unknown", nil); err != app.ErrorProfileNotFound {
+		t.Errorf("expected profile not found error, got %v", err)
+	}
+
+	if following(bob) {
+		t.Errorf("profile is followed before follow")
+	}
+
+	// Following twice is fine
+	for i := 0; i < 2; i++ {
+		if err := f.s.FollowProfile(f.ctx, bob, alice); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !following(bob) {
+		t.Errorf("profile is not followed after follow")
+	}
+
+	if following(nil) {
+		t.Errorf("profile is followed by anonymous viewer")
+	}
+
+	if err := f.s.UnfollowProfile(f.ctx, bob, alice); err != nil {
+		t.Fatal(err)
+	}
+
+	if following(bob) {
+		t.Errorf("profile is followed after unfollow")
+	}
+
+	// Unfollowing not followed profile is fine too
+	if err := f.s.UnfollowProfile(f.ctx, bob, alice); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,187 @@
+// Package storetest is a conformance test suite for the store backends. Every
+// backend runs the same suite to make sure it's interchangeable with the
+// others, e.g. lists are sorted the same way and not found cases return the
+// same values.
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dzeban/conduit/app"
+	"github.com/dzeban/conduit/article"
+	"github.com/dzeban/conduit/comment"
+	"github.com/dzeban/conduit/profile"
+	"github.com/dzeban/conduit/tag"
+	"github.com/dzeban/conduit/user"
+)
+
+// Store is the set of store interfaces covered by the suite. Comments and
+// tags are checked only as far as they depend on articles.
+type Store interface {
+	user.Store
+	article.Store
+	profile.Store
+	comment.Store
+	tag.Store
+}
+
+// Run runs the suite. newStore must return an empty store, it's called for
+// every test.
+func Run(t *testing.T, newStore func(t *testing.T) Store) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, f *fixture)
+	}{
+		{"Users", testUsers},
+		{"UniqueUser", testUniqueUser},
+		{"RefreshTokens", testRefreshTokens},
+		{"Profiles", testProfiles},
+		{"Articles", testArticles},
+		{"UpdateArticle", testUpdateArticle},
+		{"DeleteArticle", testDeleteArticle},
+		{"ListArticles", testListArticles},
+		{"ListOrder", testListOrder},
+		{"Feed", testFeed},
+		{"Favorites", testFavorites},
+		{"Search", testSearch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, &fixture{
+				t:       t,
+				ctx:     context.Background(),
+				s:       newStore(t),
+				created: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			})
+		})
+	}
+}
+
+// fixture creates test data in the store
+type fixture struct {
+	t   *testing.T
+	ctx context.Context
+	s   Store
+
+	// created is the creation time of the last article. Articles are created
+	// a minute apart, so they are listed in reverse order of creation.
+	created time.Time
+}
+
+// addUser adds user with the given name and returns it as it's stored
+func (f *fixture) addUser(name string) *app.User {
+	f.t.Helper()
+
+	u := &app.User{
+		Name:         name,
+		Email:        name + "@example.com",
+		PasswordHash: "hash",
+		Bio:          "Bio of " + name,
+	}
+	if err := f.s.AddUser(f.ctx, u); err != nil {
+		f.t.Fatal(err)
+	}
+
+	if u.Id == 0 {
+		f.t.Fatalf("id of user %s is not set on add", name)
+	}
+
+	stored, err := f.s.GetUser(f.ctx, u.Email)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	if stored == nil || *stored != *u {
+		f.t.Fatalf("added user %+v doesn't match stored user %+v", u, stored)
+	}
+
+	return stored
+}
+
+// addArticle adds article by author with the given title and tags
+func (f *fixture) addArticle(author *app.User, title string, tags ...string) *app.Article {
+	f.t.Helper()
+
+	f.created = f.created.Add(time.Minute)
+	a := &app.Article{
+		Slug:        fmt.Sprintf("%s-%d", author.Name, f.created.Unix()),
+		Title:       title,
+		Description: "Description of " + title,
+		Body:        "Body of " + title,
+		Author:      *app.ProfileFromUser(author),
+		TagList:     tags,
+		Created:     f.created,
+		Updated:     f.created,
+	}
+	if err := f.s.CreateArticle(f.ctx, a); err != nil {
+		f.t.Fatal(err)
+	}
+
+	return a
+}
+
+// getArticle returns article by slug and fails if it doesn't exist
+func (f *fixture) getArticle(slug string, viewer *app.Profile) *app.Article {
+	f.t.Helper()
+
+	a, err := f.s.GetArticle(f.ctx, slug, viewer)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	if a == nil {
+		f.t.Fatalf("article %s is not found", slug)
+	}
+
+	return a
+}
+
+// list returns slugs of articles matching the filter
+func (f *fixture) list(filter app.ArticleListFilter) []string {
+	f.t.Helper()
+
+	articles, err := f.s.ListArticles(f.ctx, &filter)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return slugs(articles...)
+}
+
+// count returns number of articles matching the filter
+func (f *fixture) count(filter app.ArticleListFilter) int {
+	f.t.Helper()
+
+	n, err := f.s.CountArticles(f.ctx, &filter)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return n
+}
+
+// equalStrings compares string slices treating nil and empty as equal
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func slugs(articles ...*app.Article) []string {
+	s := []string{}
+	for _, a := range articles {
+		s = append(s, a.Slug)
+	}
+	return s
+}
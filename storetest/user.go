@@ -0,0 +1,172 @@
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dzeban/conduit/app"
+)
+
+func testUsers(t *testing.T, f *fixture) {
+	u := f.addUser("alice")
+	if u.Name != "alice" || u.PasswordHash != "hash" || u.Bio != "Bio of alice" || u.Image != "" {
+		t.Errorf("invalid user by email: %+v", u)
+	}
+
+	byId, err := f.s.GetUserById(f.ctx, u.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byId == nil || *byId != *u {
+		t.Errorf("user by id %+v doesn't match user by email %+v", byId, u)
+	}
+
+	if got, err := f.s.GetUser(f.ctx, "unknown@example.com"); got != nil || err != nil {
+		t.Errorf("expected nil for unknown email, got %+v, %v", got, err)
+	}
+
+	if got, err := f.s.GetUserById(f.ctx, u.Id+100); got != nil || err != nil {
+		t.Errorf("expected nil for unknown id, got %+v, %v", got, err)
+	}
+
+	// Only non-empty fields are updated
+	err = f.s.UpdateUser(f.ctx, &app.User{Id: u.Id, Image: "image", Email: "new@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := f.s.GetUserById(f.ctx, u.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := *u
+	expected.Image = "image"
+	expected.Email = "new@example.com"
+	if updated == nil || *updated != expected {
+		t.Errorf("invalid user after update, expected %+v, got %+v", expected, updated)
+	}
+
+	// User is found only by the new email
+	if got, err := f.s.GetUser(f.ctx, u.Email); got != nil || err != nil {
+		t.Errorf("user is found by old email: %+v, %v", got, err)
+	}
+
+	if got, err := f.s.GetUser(f.ctx, "new@example.com"); got == nil || got.Id != u.Id || err != nil {
+		t.Errorf("user is not found by new email: %+v, %v", got, err)
+	}
+}
+
+func testUniqueUser(t *testing.T, f *fixture) {
+	alice := f.addUser("alice")
+	bob := f.addUser("bob")
+
+	tests := []struct {
+		name string
+		user app.User
+	}{
+		{"Email", app.User{Name: "other", Email: alice.Email, PasswordHash: "hash"}},
+		{"Name", app.User{Name: alice.Name, Email: "other@example.com", PasswordHash: "hash"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := tt.user
+			if err := f.s.AddUser(f.ctx, &user); err == nil {
+				t.Errorf("duplicate user is added")
+			}
+
+			// Update to the taken email or name fails too
+			user.Id = bob.Id
+			if err := f.s.UpdateUser(f.ctx, &user); err == nil {
+				t.Errorf("user is updated to duplicate")
+			}
+
+			got, err := f.s.GetUserById(f.ctx, bob.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got == nil || *got != *bob {
+				t.Errorf("user is changed by failed update: %+v", got)
+			}
+
+			// Email lookup is not changed either
+			got, err = f.s.GetUser(f.ctx, bob.Email)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got == nil || *got != *bob {
+				t.Errorf("user is not found by email after failed update: %+v", got)
+			}
+
+			var expected *app.User
+			if user.Email == alice.Email {
+				expected = alice
+			}
+
+			got, err = f.s.GetUser(f.ctx, user.Email)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (got == nil) != (expected == nil) || (got != nil && *got != *expected) {
+				t.Errorf("invalid user by email %s after failed update, expected %+v, got %+v", user.Email, expected, got)
+			}
+		})
+	}
+}
+
+func testRefreshTokens(t *testing.T, f *fixture) {
+	u := f.addUser("alice")
+
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tokens := []app.RefreshToken{
+		{Hash: "first", UserId: u.Id, Created: created, Expires: created.Add(time.Hour)},
+		{Hash: "second", UserId: u.Id, Created: created, Expires: created.Add(2 * time.Hour)},
+	}
+
+	for i := range tokens {
+		if err := f.s.AddRefreshToken(f.ctx, &tokens[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got, err := f.s.DeleteRefreshToken(f.ctx, "unknown"); got != nil || err != nil {
+		t.Errorf("expected nil for unknown token, got %+v, %v", got, err)
+	}
+
+	// Token of the user is not deleted on behalf of the other user
+	other := f.addUser("bob")
+	if got, err := f.s.DeleteUserRefreshToken(f.ctx, other.Id, tokens[0].Hash); got != nil || err != nil {
+		t.Errorf("expected nil for token of other user, got %+v, %v", got, err)
+	}
+
+	for _, token := range tokens {
+		got, err := f.s.DeleteRefreshToken(f.ctx, token.Hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got == nil || got.Hash != token.Hash || got.UserId != token.UserId ||
+			!got.Created.Equal(token.Created) || !got.Expires.Equal(token.Expires) {
+			t.Errorf("invalid deleted token, expected %+v, got %+v", token, got)
+		}
+
+		// Token can be used only once
+		if got, err := f.s.DeleteRefreshToken(f.ctx, token.Hash); got != nil || err != nil {
+			t.Errorf("expected nil for deleted token, got %+v, %v", got, err)
+		}
+	}
+
+	token := app.RefreshToken{Hash: "third", UserId: u.Id, Created: created, Expires: created.Add(time.Hour)}
+	if err := f.s.AddRefreshToken(f.ctx, &token); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := f.s.DeleteUserRefreshToken(f.ctx, u.Id, token.Hash); got == nil || err != nil {
+		t.Errorf("failed to delete token of the user, got %+v, %v", got, err)
+	}
+
+	if got, err := f.s.DeleteRefreshToken(f.ctx, token.Hash); got != nil || err != nil {
+		t.Errorf("expected nil for deleted token, got %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,52 @@
+package tag
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+	"github.com/dzeban/conduit/transport"
+)
+
+type Server struct {
+	router  *chi.Mux
+	service *Service
+}
+
+func NewHTTP(store Store) (*Server, error) {
+	s := &Server{
+		router:  chi.NewRouter(),
+		service: NewService(store),
+	}
+
+	s.router.Get("/", transport.WithError(s.HandleList))
+
+	return s, nil
+}
+
+// ServeHTTP implements http.handler interface and uses router ServeHTTP method
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
+
+type Response struct {
+	Tags []string `json:"tags"`
+}
+
+func (s *Server) HandleList(w http.ResponseWriter, r *http.Request) error {
+	tags, err := s.service.List(r.Context())
+	if err != nil {
+		return err
+	}
+
+	resp, err := json.Marshal(Response{Tags: tags})
+	if err != nil {
+		return app.InternalError(errors.Wrap(err, "json.Marshal"))
+	}
+
+	w.Write(resp)
+	return nil
+}
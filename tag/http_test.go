@@ -0,0 +1,41 @@
+package tag
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"github.com/dzeban/conduit/mock"
+)
+
+func TestListHandler(t *testing.T) {
+	s, err := NewHTTP(mock.NewArticleStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	s.ServeHTTP(rr, req)
+
+	resp := rr.Result()
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("incorrect status, expected %v, got %v, body %v", http.StatusOK, resp.StatusCode, string(body))
+	}
+
+	var r Response
+	err = json.Unmarshal(body, &r)
+	if err != nil {
+		t.Fatalf("invalid response body: %v", err)
+	}
+
+	if diff := deep.Equal(r.Tags, mock.ArticleValid.TagList); diff != nil {
+		t.Error(diff)
+	}
+}
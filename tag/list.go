@@ -0,0 +1,19 @@
+package tag
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+)
+
+// List returns all tags used in articles
+func (s *Service) List(ctx context.Context) ([]string, error) {
+	tags, err := s.store.ListTags(ctx)
+	if err != nil {
+		return nil, app.InternalError(errors.Wrap(err, "failed to get list of tags"))
+	}
+
+	return tags, nil
+}
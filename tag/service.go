@@ -0,0 +1,18 @@
+package tag
+
+import "context"
+
+// Store defines an interface to work with tags
+type Store interface {
+	ListTags(ctx context.Context) ([]string, error)
+}
+
+// Service provides methods for tags
+type Service struct {
+	store Store
+}
+
+// NewService creates new instance of the service with provided store
+func NewService(store Store) *Service {
+	return &Service{store}
+}
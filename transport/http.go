@@ -13,8 +13,27 @@ type ErrorResponse struct {
 	Errors Errors `json:"errors"`
 }
 
-type Errors struct {
-	Body []string `json:"body"`
+// Errors maps request fields to their problems. Errors that are not related
+// to a particular field are reported under the "body" key.
+type Errors map[string][]string
+
+// StatusCode returns HTTP status code for the app error type
+func StatusCode(t app.ErrorType) int {
+	switch t {
+	case app.ErrorTypeInternal:
+		return http.StatusInternalServerError
+	case app.ErrorTypeAuth:
+		return http.StatusUnauthorized
+	case app.ErrorTypeForbidden:
+		return http.StatusForbidden
+	case app.ErrorTypeNotFound:
+		return http.StatusNotFound
+	case app.ErrorTypeConflict:
+		return http.StatusConflict
+	default:
+		// Service and validation errors
+		return http.StatusUnprocessableEntity
+	}
 }
 
 type HandlerWithError func(http.ResponseWriter, *http.Request) error
@@ -33,27 +52,24 @@ func WithError(h HandlerWithError) http.HandlerFunc {
 				return
 			}
 
-			switch e.Type {
 			// Internal server errors are not returned to user, they are logged
-			case app.ErrorTypeInternal:
+			if e.Type == app.ErrorTypeInternal {
 				log.Printf("internal server error: %+v", err)
 				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 				return
-
-			case app.ErrorTypeAuth:
-				w.WriteHeader(http.StatusUnauthorized)
-
-			default:
-				w.WriteHeader(http.StatusUnprocessableEntity)
 			}
 
 			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(StatusCode(e.Type))
+
+			errs := Errors{"body": []string{err.Error()}}
+
+			var fieldErrs app.FieldErrors
+			if errors.As(err, &fieldErrs) {
+				errs = Errors(fieldErrs)
+			}
 
-			err = json.NewEncoder(w).Encode(ErrorResponse{
-				Errors: Errors{
-					Body: []string{err.Error()},
-				},
-			})
+			err = json.NewEncoder(w).Encode(ErrorResponse{Errors: errs})
 			if err != nil {
 				log.Printf("failed to marshal error response: %+v", err)
 				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
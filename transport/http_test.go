@@ -0,0 +1,95 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dzeban/conduit/app"
+)
+
+func TestWithError(t *testing.T) {
+	testErr := errors.New("test error")
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Service", app.ServiceError(testErr), http.StatusUnprocessableEntity},
+		{"Validation", app.ValidationError(testErr), http.StatusUnprocessableEntity},
+		{"Auth", app.AuthError(testErr), http.StatusUnauthorized},
+		{"Forbidden", app.ForbiddenError(testErr), http.StatusForbidden},
+		{"NotFound", app.NotFoundError(testErr), http.StatusNotFound},
+		{"Conflict", app.ConflictError(testErr), http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := WithError(func(w http.ResponseWriter, r *http.Request) error {
+				return tt.err
+			})
+
+			rr := httptest.NewRecorder()
+			h(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			resp := rr.Result()
+			if resp.StatusCode != tt.status {
+				t.Errorf("incorrect status, expected %v, got %v", tt.status, resp.StatusCode)
+			}
+
+			if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+				t.Errorf("incorrect content type '%v'", ct)
+			}
+
+			var errResp ErrorResponse
+			err := json.NewDecoder(resp.Body).Decode(&errResp)
+			if err != nil {
+				t.Fatalf("failed to decode error response: %v", err)
+			}
+
+			body := errResp.Errors["body"]
+			if len(body) != 1 || body[0] != testErr.Error() {
+				t.Errorf("incorrect error response %+v", errResp)
+			}
+		})
+	}
+}
+
+func TestWithErrorFields(t *testing.T) {
+	fieldErrs := app.FieldErrors{}
+	fieldErrs.Add("title", "is required")
+	fieldErrs.Add("body", "is required")
+
+	h := WithError(func(w http.ResponseWriter, r *http.Request) error {
+		return app.ValidationError(fieldErrs)
+	})
+
+	rr := httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("incorrect status, expected %v, got %v", http.StatusUnprocessableEntity, rr.Code)
+	}
+
+	expected := `{"errors":{"body":["is required"],"title":["is required"]}}`
+	if body := strings.TrimSpace(rr.Body.String()); body != expected {
+		t.Errorf("incorrect error response, expected %v, got %v", expected, body)
+	}
+}
+
+func TestWithErrorInternal(t *testing.T) {
+	h := WithError(func(w http.ResponseWriter, r *http.Request) error {
+		return app.InternalError(errors.New("secret details"))
+	})
+
+	rr := httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("incorrect status, expected %v, got %v", http.StatusInternalServerError, rr.Code)
+	}
+}
@@ -1,8 +1,11 @@
 package user
 
 import (
+	"context"
 	"encoding/json"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/pkg/errors"
@@ -16,26 +19,57 @@ type Server struct {
 	router *chi.Mux
 
 	service *Service
-	secret  []byte
+	signer  *jwt.Signer
+	tokens  TokenConfig
 }
 
-func NewHTTP(store Store, secret []byte) (*Server, error) {
+// TokenConfig describes lifetime of tokens issued to users and where revoked
+// tokens are kept. Zero values are replaced with defaults.
+type TokenConfig struct {
+	Lifetime        time.Duration
+	RefreshLifetime time.Duration
+	Revocations     jwt.RevocationStore
+}
+
+func NewHTTP(store Store, signer *jwt.Signer, tokens TokenConfig, authOpts ...jwt.AuthOption) (*Server, error) {
+	if tokens.Lifetime == 0 {
+		tokens.Lifetime = jwt.DefaultLifetime
+	}
+
+	if tokens.RefreshLifetime == 0 {
+		tokens.RefreshLifetime = DefaultRefreshLifetime
+	}
+
+	if tokens.Revocations == nil {
+		tokens.Revocations = jwt.NewMemoryRevocations()
+	}
+
+	// Tokens revoked on logout must be rejected by the server itself, and
+	// users are always loaded from the store. Passed options take precedence.
+	authOpts = append([]jwt.AuthOption{
+		jwt.WithRevocations(tokens.Revocations),
+		jwt.WithUserLookup(store),
+	}, authOpts...)
+
 	s := &Server{
 		router:  chi.NewRouter(),
 		service: NewService(store),
-		secret:  secret,
+		signer:  signer,
+		tokens:  tokens,
 	}
 
 	// Unauthenticated endpoints
 	s.router.Post("/", transport.WithError(s.HandleUserRegister))
 	s.router.Post("/login", transport.WithError(s.HandleUserLogin))
+	s.router.Post("/token/refresh", transport.WithError(s.HandleTokenRefresh))
 
 	// Endpoints protected by JWT auth
 	s.router.Group(func(r chi.Router) {
-		r.Use(jwt.Auth(s.secret, jwt.AuthTypeRequired))
+		r.Use(jwt.Auth(s.signer, jwt.AuthTypeRequired, authOpts...))
 
 		r.Get("/", transport.WithError(s.HandleUserGet))
 		r.Put("/", transport.WithError(s.HandleUserUpdate))
+		r.Post("/logout", transport.WithError(s.HandleLogout))
 	})
 
 	return s, nil
@@ -52,6 +86,23 @@ type Response struct {
 	User app.User `json:"user"`
 }
 
+// issueTokens sets new JWT and refresh token for the user
+func (s *Server) issueTokens(ctx context.Context, user *app.User) error {
+	token, err := jwt.NewWithLifetime(user, s.signer, s.tokens.Lifetime)
+	if err != nil {
+		return app.InternalError(errors.Wrap(err, "jwt.New"))
+	}
+
+	refreshToken, err := s.service.NewRefreshToken(ctx, user, s.tokens.RefreshLifetime)
+	if err != nil {
+		return err
+	}
+
+	user.Token = token
+	user.RefreshToken = refreshToken
+	return nil
+}
+
 func (s *Server) HandleUserLogin(w http.ResponseWriter, r *http.Request) error {
 	// Decode user request from JSON body
 	decoder := json.NewDecoder(r.Body)
@@ -62,19 +113,18 @@ func (s *Server) HandleUserLogin(w http.ResponseWriter, r *http.Request) error {
 	}
 
 	// Perform login in service
-	user, err := s.service.Login(&req)
+	user, err := s.service.Login(r.Context(), &req)
 	if err != nil {
 		return err
 	}
 
-	// Generate JWT
-	token, err := jwt.New(user, s.secret)
+	// Generate JWT and refresh token
+	err = s.issueTokens(r.Context(), user)
 	if err != nil {
-		return app.InternalError(errors.Wrap(err, "jwt.New"))
+		return err
 	}
 
-	// Prepare and send reply with user data, including token
-	user.Token = token
+	// Prepare and send reply with user data, including tokens
 	jsonUser, err := json.Marshal(Response{User: *user})
 	if err != nil {
 		return app.InternalError(errors.Wrap(err, "json.Marshal"))
@@ -94,19 +144,18 @@ func (s *Server) HandleUserRegister(w http.ResponseWriter, r *http.Request) erro
 	}
 
 	// Perform register in service
-	user, err := s.service.Register(&req)
+	user, err := s.service.Register(r.Context(), &req)
 	if err != nil {
 		return err
 	}
 
-	// Generate JWT
-	token, err := jwt.New(user, s.secret)
+	// Generate JWT and refresh token
+	err = s.issueTokens(r.Context(), user)
 	if err != nil {
-		return app.InternalError(errors.Wrap(err, "jwt.New"))
+		return err
 	}
 
-	// Prepare and send reply with user data, including token
-	user.Token = token
+	// Prepare and send reply with user data, including tokens
 	jsonUser, err := json.Marshal(Response{User: *user})
 	if err != nil {
 		return app.InternalError(errors.Wrap(err, "json.Marshal"))
@@ -117,6 +166,37 @@ func (s *Server) HandleUserRegister(w http.ResponseWriter, r *http.Request) erro
 	return nil
 }
 
+// HandleTokenRefresh exchanges refresh token for the new JWT and refresh
+// token. It doesn't require authentication because JWT may be expired.
+func (s *Server) HandleTokenRefresh(w http.ResponseWriter, r *http.Request) error {
+	// Decode user request from JSON body
+	decoder := json.NewDecoder(r.Body)
+	var req RefreshRequest
+	err := decoder.Decode(&req)
+	if err != nil {
+		return app.ServiceError(errorInvalidRequest)
+	}
+
+	user, err := s.service.Refresh(r.Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	// Generate JWT and refresh token
+	err = s.issueTokens(r.Context(), user)
+	if err != nil {
+		return err
+	}
+
+	jsonUser, err := json.Marshal(Response{User: *user})
+	if err != nil {
+		return app.InternalError(errors.Wrap(err, "json.Marshal"))
+	}
+
+	w.Write(jsonUser)
+	return nil
+}
+
 // HandleUserGet gets the currently logged-in user. Requires authentication.
 func (s *Server) HandleUserGet(w http.ResponseWriter, r *http.Request) error {
 	currentUser, ok := app.UserFromContext(r.Context())
@@ -124,7 +204,7 @@ func (s *Server) HandleUserGet(w http.ResponseWriter, r *http.Request) error {
 		return app.AuthError(app.ErrorUserNotInContext)
 	}
 
-	u, err := s.service.Get(currentUser.Email)
+	u, err := s.service.Get(r.Context(), currentUser.Email)
 	if err != nil {
 		return err
 	}
@@ -155,16 +235,16 @@ func (s *Server) HandleUserUpdate(w http.ResponseWriter, r *http.Request) error
 
 	// Check that user updates itself
 	if req.User.Email != "" && req.User.Email != currentUser.Email {
-		return app.AuthError(errorUserUpdateForbidden)
+		return app.ForbiddenError(errorUserUpdateForbidden)
 	}
 
-	u, err := s.service.Update(currentUser.Id, &req)
+	u, err := s.service.Update(r.Context(), currentUser.Id, &req)
 	if err != nil {
 		return err
 	}
 
 	// Regenerate JWT
-	token, err := jwt.New(u, s.secret)
+	token, err := jwt.NewWithLifetime(u, s.signer, s.tokens.Lifetime)
 	if err != nil {
 		return app.InternalError(errors.Wrap(err, "jwt.New"))
 	}
@@ -178,3 +258,41 @@ func (s *Server) HandleUserUpdate(w http.ResponseWriter, r *http.Request) error
 	w.Write(jsonUser)
 	return nil
 }
+
+// HandleLogout revokes JWT used for the request. Refresh token of the current
+// user passed in the optional request body is revoked too. Requires
+// authentication.
+func (s *Server) HandleLogout(w http.ResponseWriter, r *http.Request) error {
+	claims, ok := jwt.ClaimsFromContext(r.Context())
+	if !ok {
+		return app.AuthError(app.ErrorUserNotInContext)
+	}
+
+	currentUser, ok := app.UserFromContext(r.Context())
+	if !ok {
+		return app.AuthError(app.ErrorUserNotInContext)
+	}
+
+	// Decode optional request body with refresh token
+	decoder := json.NewDecoder(r.Body)
+	var req RefreshRequest
+	err := decoder.Decode(&req)
+	if err != nil && err != io.EOF {
+		return app.ServiceError(errorInvalidRequest)
+	}
+
+	err = s.tokens.Revocations.RevokeToken(r.Context(), claims.Id, time.Unix(claims.ExpiresAt, 0))
+	if err != nil {
+		return app.InternalError(errors.Wrap(err, "failed to revoke token"))
+	}
+
+	if req.User.RefreshToken != "" {
+		err = s.service.RevokeRefreshToken(r.Context(), currentUser, req.User.RefreshToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	w.Write(nil)
+	return nil
+}
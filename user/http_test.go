@@ -2,6 +2,7 @@ package user
 
 import (
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -13,7 +14,7 @@ import (
 	"github.com/dzeban/conduit/mock"
 )
 
-const testSecret = "test"
+var testSigner = jwt.NewHMACSigner([]byte("test-secret-of-at-least-32-bytes"))
 
 func TestLoginHandler(t *testing.T) {
 	tests := []struct {
@@ -38,7 +39,13 @@ func TestLoginHandler(t *testing.T) {
 			"IncorrectPassword",
 			`{"user":{"email":"test@example.com","password":"incorrect"}}`,
 			http.StatusUnauthorized,
-			errorPasswordMismatch,
+			errorInvalidCredentials,
+		},
+		{
+			"UnknownEmail",
+			`{"user":{"email":"no_such_user@example.com","password":"incorrect"}}`,
+			http.StatusUnauthorized,
+			errorInvalidCredentials,
 		},
 		{
 			"valid",
@@ -48,7 +55,7 @@ func TestLoginHandler(t *testing.T) {
 		},
 	}
 
-	s, err := NewHTTP(mock.NewUserStore(), []byte(testSecret))
+	s, err := NewHTTP(mock.NewUserStore(), testSigner, TokenConfig{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -94,33 +101,32 @@ func TestGetHandler(t *testing.T) {
 			nil,
 		},
 		{
+			// Token of the user that doesn't exist (e.g. deleted) is rejected
 			"NotFound",
 			&app.User{
+				Id:    999,
 				Name:  "no_such_user",
 				Email: "no_such_user@example.com",
 			},
-			http.StatusUnprocessableEntity,
-			errorUserNotFound,
+			http.StatusUnauthorized,
+			nil,
 		},
 		{
 			"Valid",
-			&app.User{
-				Name:  "test",
-				Email: "test@example.com",
-			},
+			&mock.UserValid,
 			http.StatusOK,
 			nil,
 		},
 	}
 
-	s, err := NewHTTP(mock.NewUserStore(), []byte(testSecret))
+	s, err := NewHTTP(mock.NewUserStore(), testSigner, TokenConfig{})
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			token, err := jwt.New(tt.contextUser, []byte(testSecret))
+			token, err := jwt.New(tt.contextUser, testSigner)
 			if err != nil {
 				t.Errorf("failed to make JWT")
 				return
@@ -175,17 +181,17 @@ func TestRegisterHandler(t *testing.T) {
 			"NoUsername",
 			`{"user":{"email":"test@example.com","password":"test"}}`,
 			http.StatusUnprocessableEntity,
-			errorUsernameIsRequired,
+			errors.New(`"username":["is required"]`), // field error in response body
 		},
 		{
 			"valid",
-			`{"user":{"username": "new_register", "email":"new_register@example.com","password":"new_register"}}`,
+			`{"user":{"username": "new_register", "email":"new_register@example.com","password":"correct horse battery staple"}}`,
 			http.StatusCreated,
 			nil,
 		},
 	}
 
-	s, err := NewHTTP(mock.NewUserStore(), []byte(testSecret))
+	s, err := NewHTTP(mock.NewUserStore(), testSigner, TokenConfig{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -235,14 +241,14 @@ func TestLoginRegisterHandlerToken(t *testing.T) {
 		},
 		{
 			"Register",
-			`{"user":{"username": "new_register", "email":"new_register@example.com","password":"new_register"}}`,
+			`{"user":{"username": "new_register", "email":"new_register@example.com","password":"correct horse battery staple"}}`,
 			"/",
 			http.StatusCreated,
 			"new_register",
 		},
 	}
 
-	s, err := NewHTTP(mock.NewUserStore(), []byte(testSecret))
+	s, err := NewHTTP(mock.NewUserStore(), testSigner, TokenConfig{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -316,23 +322,23 @@ func TestUpdateHandler(t *testing.T) {
 		{
 			"Forbidden",
 			`{"user":{"id": 2, "email":"updated@example.com","password":"test"}}`,
-			http.StatusUnauthorized,
+			http.StatusForbidden,
 			errorUserUpdateForbidden,
 		},
 		{
 			"Valid",
-			`{"user":{"id": 1, "email":"test@example.com","password":"test"}}`,
+			`{"user":{"id": 1, "email":"test@example.com","password":"correct horse battery staple"}}`,
 			http.StatusOK,
 			nil,
 		},
 	}
 
-	s, err := NewHTTP(mock.NewUserStore(), []byte(testSecret))
+	s, err := NewHTTP(mock.NewUserStore(), testSigner, TokenConfig{})
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	token, err := jwt.New(&mock.UserValid, []byte(testSecret))
+	token, err := jwt.New(&mock.UserValid, testSigner)
 	if err != nil {
 		t.Fatal("failed to make JWT")
 	}
@@ -363,3 +369,42 @@ func TestUpdateHandler(t *testing.T) {
 	}
 
 }
+
+func TestLogoutHandler(t *testing.T) {
+	s, err := NewHTTP(mock.NewUserStore(), testSigner, TokenConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := jwt.New(&mock.UserValid, testSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"BeforeLogout", http.MethodGet, "/", http.StatusOK},
+		{"Logout", http.MethodPost, "/logout", http.StatusOK},
+		{"AfterLogout", http.MethodGet, "/", http.StatusUnauthorized},
+		{"LogoutTwice", http.MethodPost, "/logout", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Add("Authorization", "Token "+token)
+
+			s.ServeHTTP(rr, req)
+
+			if rr.Code != tt.status {
+				t.Errorf("incorrect status, expected %v, got %v", tt.status, rr.Code)
+				t.Errorf("resp body: %v", rr.Body.String())
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package user
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 
 	"github.com/dzeban/conduit/app"
@@ -17,34 +19,40 @@ type LoginUser struct {
 	Password string `json:"password"` // NOTE: Plaintext password from user
 }
 
+// Validate checks the request and returns app.FieldErrors with all of the
+// problems found
 func (r *LoginRequest) Validate() error {
+	errs := app.FieldErrors{}
+
 	if r.User.Email == "" {
-		return errorEmailIsRequired
+		errs.Add("email", "is required")
 	}
 
 	if r.User.Password == "" {
-		return errorPasswordIsRequired
+		errs.Add("password", "is required")
 	}
 
-	return nil
+	return errs.Err()
 }
 
 // Login checks the user request and logins the user
-func (s *Service) Login(req *LoginRequest) (*app.User, error) {
+func (s *Service) Login(ctx context.Context, req *LoginRequest) (*app.User, error) {
 	// Validate request
 	err := req.Validate()
 	if err != nil {
-		return nil, app.ServiceError(err)
+		return nil, app.ValidationError(err)
 	}
 
 	// Lookup user by email
-	user, err := s.store.GetUser(req.User.Email)
+	user, err := s.store.GetUser(ctx, req.User.Email)
 	if err != nil {
 		return nil, app.InternalError(errors.Wrap(err, "failed to get user"))
 	}
 
+	// Unknown email and wrong password are indistinguishable for the client,
+	// otherwise login could be used to find out registered emails
 	if user == nil {
-		return nil, app.ServiceError(errorUserNotFound)
+		return nil, app.AuthError(errorInvalidCredentials)
 	}
 
 	// Check password
@@ -54,7 +62,27 @@ func (s *Service) Login(req *LoginRequest) (*app.User, error) {
 	}
 
 	if !ok {
-		return nil, app.AuthError(errorPasswordMismatch)
+		return nil, app.AuthError(errorInvalidCredentials)
+	}
+
+	// Upgrade hash created with outdated params while we know the password
+	rehash, err := password.NeedsRehash(user.PasswordHash)
+	if err != nil {
+		return nil, app.InternalError(errors.Wrap(err, "failed to check password hash params"))
+	}
+
+	if rehash {
+		hash, err := password.HashAndEncode(req.User.Password)
+		if err != nil {
+			return nil, app.InternalError(errors.Wrap(err, "failed to create password hash"))
+		}
+
+		err = s.store.UpdateUser(ctx, &app.User{Id: user.Id, PasswordHash: hash})
+		if err != nil {
+			return nil, app.InternalError(errors.Wrap(err, "failed to update password hash"))
+		}
+
+		user.PasswordHash = hash
 	}
 
 	// Return the user
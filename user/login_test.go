@@ -1,11 +1,15 @@
 package user
 
 import (
+	"context"
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/dzeban/conduit/app"
 	"github.com/dzeban/conduit/mock"
+	"github.com/dzeban/conduit/password"
 )
 
 func TestLogin(t *testing.T) {
@@ -19,8 +23,11 @@ func TestLogin(t *testing.T) {
 		{
 			"EmptyValidation",
 			&LoginRequest{},
-			app.ErrorTypeService,
-			nil, // Don't test for specific error because validation order may be changed
+			app.ErrorTypeValidation,
+			app.FieldErrors{
+				"email":    {"is required"},
+				"password": {"is required"},
+			},
 		},
 		{
 			"EmailValidation",
@@ -29,8 +36,8 @@ func TestLogin(t *testing.T) {
 					Password: mock.TestPassword,
 				},
 			},
-			app.ErrorTypeService,
-			errorEmailIsRequired,
+			app.ErrorTypeValidation,
+			app.FieldErrors{"email": {"is required"}},
 		},
 		{
 			"PasswordValidation",
@@ -39,8 +46,8 @@ func TestLogin(t *testing.T) {
 					Email: mock.UserValid.Email,
 				},
 			},
-			app.ErrorTypeService,
-			errorPasswordIsRequired,
+			app.ErrorTypeValidation,
+			app.FieldErrors{"password": {"is required"}},
 		},
 		{
 			"NonExist",
@@ -50,8 +57,8 @@ func TestLogin(t *testing.T) {
 					Password: "abc",
 				},
 			},
-			app.ErrorTypeService,
-			errorUserNotFound,
+			app.ErrorTypeAuth,
+			errorInvalidCredentials,
 		},
 		{
 			"InvalidPassword",
@@ -62,7 +69,7 @@ func TestLogin(t *testing.T) {
 				},
 			},
 			app.ErrorTypeAuth,
-			errorPasswordMismatch,
+			errorInvalidCredentials,
 		},
 		{
 			"InvalidPasswordHash",
@@ -92,7 +99,7 @@ func TestLogin(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := s.Login(tt.req)
+			_, err := s.Login(context.Background(), tt.req)
 			if err != nil {
 				var e app.Error
 				// Unwrap service.Error
@@ -109,7 +116,7 @@ func TestLogin(t *testing.T) {
 
 				// Check error value
 				if tt.err != nil {
-					if e.Err != tt.err {
+					if !reflect.DeepEqual(e.Err, tt.err) {
 						t.Errorf("Login(%v): invalid error value: expected %v, got %v", tt.req, tt.err, e.Err)
 						return
 					}
@@ -119,3 +126,95 @@ func TestLogin(t *testing.T) {
 		})
 	}
 }
+
+func TestLoginCancelled(t *testing.T) {
+	s := NewService(mock.NewUserStore())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.Login(ctx, &LoginRequest{
+		LoginUser{
+			Email:    mock.UserValid.Email,
+			Password: mock.TestPassword,
+		},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Login with cancelled context: expected '%v', got '%v'", context.Canceled, err)
+	}
+}
+
+func TestLoginRehash(t *testing.T) {
+	defaults := password.Defaults()
+	defer password.SetDefaults(defaults)
+
+	stronger := defaults
+	stronger.Iterations++
+	err := password.SetDefaults(stronger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := mock.NewUserStore()
+	s := NewService(store)
+
+	_, err = s.Login(context.Background(), &LoginRequest{
+		LoginUser{
+			Email:    mock.UserValid.Email,
+			Password: mock.TestPassword,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := store.ById[mock.UserValid.Id].PasswordHash
+	if hash == mock.TestPasswordHash {
+		t.Fatalf("password hash is not upgraded on login")
+	}
+
+	rehash, err := password.NeedsRehash(hash)
+	if err != nil || rehash {
+		t.Errorf("upgraded hash needs rehash: %v, error %v", rehash, err)
+	}
+
+	ok, err := password.Check(mock.TestPassword, hash)
+	if err != nil || !ok {
+		t.Errorf("upgraded hash doesn't match password: %v, error %v", ok, err)
+	}
+}
+
+func TestLoginRehashLegacy(t *testing.T) {
+	store := mock.NewUserStore()
+	s := NewService(store)
+
+	// bcrypt hash of mock.TestPassword imported from legacy system
+	legacy := "$2a$04$7hwY4UqqKN8jf.hYwWp8be8NCVTiwRViNKf9OCQb.UlkWNXOHpuwO"
+	err := store.UpdateUser(context.Background(), &app.User{
+		Id:           mock.UserValid.Id,
+		PasswordHash: legacy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.Login(context.Background(), &LoginRequest{
+		LoginUser{
+			Email:    mock.UserValid.Email,
+			Password: mock.TestPassword,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := store.ById[mock.UserValid.Id].PasswordHash
+	if !strings.HasPrefix(hash, "$argon2id$") {
+		t.Fatalf("legacy password hash is not upgraded on login: %v", hash)
+	}
+
+	ok, err := password.Check(mock.TestPassword, hash)
+	if err != nil || !ok {
+		t.Errorf("upgraded hash doesn't match password: %v, error %v", ok, err)
+	}
+}
@@ -0,0 +1,113 @@
+package user
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/dchest/uniuri"
+	"github.com/pkg/errors"
+
+	"github.com/dzeban/conduit/app"
+)
+
+const (
+	// DefaultRefreshLifetime is the lifetime of refresh tokens when it's not
+	// configured
+	DefaultRefreshLifetime = 30 * 24 * time.Hour
+
+	// Length of random refresh token
+	refreshTokenLen = 64
+)
+
+// RefreshRequest describes request JSON for token refresh handler
+type RefreshRequest struct {
+	User RefreshUser `json:"user"`
+}
+
+type RefreshUser struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// Validate checks the request and returns app.FieldErrors with all of the
+// problems found
+func (r *RefreshRequest) Validate() error {
+	errs := app.FieldErrors{}
+
+	if r.User.RefreshToken == "" {
+		errs.Add("refreshToken", "is required")
+	}
+
+	return errs.Err()
+}
+
+// hashRefreshToken returns hash of the token that is kept in the store
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewRefreshToken creates refresh token for the user that expires after
+// lifetime
+func (s *Service) NewRefreshToken(ctx context.Context, u *app.User, lifetime time.Duration) (string, error) {
+	token := uniuri.NewLen(refreshTokenLen)
+
+	now := time.Now()
+	err := s.store.AddRefreshToken(ctx, &app.RefreshToken{
+		Hash:    hashRefreshToken(token),
+		UserId:  u.Id,
+		Created: now,
+		Expires: now.Add(lifetime),
+	})
+	if err != nil {
+		return "", app.InternalError(errors.Wrap(err, "failed to add refresh token"))
+	}
+
+	return token, nil
+}
+
+// Refresh exchanges refresh token for the user it was issued to. Refresh token
+// is used only once, the caller is expected to issue a new one.
+func (s *Service) Refresh(ctx context.Context, req *RefreshRequest) (*app.User, error) {
+	// Validate request
+	err := req.Validate()
+	if err != nil {
+		return nil, app.ValidationError(err)
+	}
+
+	t, err := s.store.DeleteRefreshToken(ctx, hashRefreshToken(req.User.RefreshToken))
+	if err != nil {
+		return nil, app.InternalError(errors.Wrap(err, "failed to delete refresh token"))
+	}
+
+	if t == nil {
+		return nil, app.AuthError(errorRefreshTokenInvalid)
+	}
+
+	if time.Now().After(t.Expires) {
+		return nil, app.AuthError(errorRefreshTokenExpired)
+	}
+
+	u, err := s.store.GetUserById(ctx, t.UserId)
+	if err != nil {
+		return nil, app.InternalError(errors.Wrap(err, "failed to get user"))
+	}
+
+	if u == nil {
+		return nil, app.AuthError(errorRefreshTokenInvalid)
+	}
+
+	return u, nil
+}
+
+// RevokeRefreshToken deletes refresh token of the user so it can't be used
+// anymore. Tokens of other users are left intact.
+func (s *Service) RevokeRefreshToken(ctx context.Context, user *app.User, token string) error {
+	_, err := s.store.DeleteUserRefreshToken(ctx, user.Id, hashRefreshToken(token))
+	if err != nil {
+		return app.InternalError(errors.Wrap(err, "failed to delete refresh token"))
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dzeban/conduit/app"
+	"github.com/dzeban/conduit/mock"
+)
+
+func TestRefresh(t *testing.T) {
+	s := NewService(mock.NewUserStore())
+
+	token, err := s.NewRefreshToken(context.Background(), &mock.UserValid, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &RefreshRequest{RefreshUser{RefreshToken: token}}
+
+	u, err := s.Refresh(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Id != mock.UserValid.Id {
+		t.Errorf("invalid user after refresh: expected %v, got %v", mock.UserValid.Id, u.Id)
+	}
+
+	// Refresh token can't be used twice
+	_, err = s.Refresh(context.Background(), req)
+	if !errors.Is(err, errorRefreshTokenInvalid) {
+		t.Errorf("invalid error for reused token, expected '%v', got '%v'", errorRefreshTokenInvalid, err)
+	}
+}
+
+func TestRefreshExpired(t *testing.T) {
+	s := NewService(mock.NewUserStore())
+
+	token, err := s.NewRefreshToken(context.Background(), &mock.UserValid, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.Refresh(context.Background(), &RefreshRequest{RefreshUser{RefreshToken: token}})
+
+	var e app.Error
+	if !errors.As(err, &e) || e.Type != app.ErrorTypeAuth || e.Err != errorRefreshTokenExpired {
+		t.Errorf("invalid error for expired token, expected '%v', got '%v'", errorRefreshTokenExpired, err)
+	}
+}
+
+func TestRevokeRefreshToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    *app.User
+		revoked bool
+	}{
+		{"Owner", &mock.UserValid, true},
+		{"OtherUser", &mock.UserUpdatedUsername, false},
+	}
+
+	s := NewService(mock.NewUserStore())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := s.NewRefreshToken(context.Background(), &mock.UserValid, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = s.RevokeRefreshToken(context.Background(), tt.user, token)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = s.Refresh(context.Background(), &RefreshRequest{RefreshUser{RefreshToken: token}})
+			if revoked := errors.Is(err, errorRefreshTokenInvalid); revoked != tt.revoked {
+				t.Errorf("invalid token state, expected revoked %v, got error '%v'", tt.revoked, err)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package user
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 
 	"github.com/dzeban/conduit/app"
@@ -17,38 +19,46 @@ type RegisterUser struct {
 	Password string `json:"password"` // NOTE: Plaintext password from user
 }
 
+// Validate checks the request and returns app.FieldErrors with all of the
+// problems found
 func (r *RegisterRequest) Validate() error {
+	errs := app.FieldErrors{}
+
 	if r.User.Username == "" {
-		return errorUsernameIsRequired
+		errs.Add("username", "is required")
 	}
 
 	if r.User.Email == "" {
-		return errorEmailIsRequired
+		errs.Add("email", "is required")
 	}
 
 	if r.User.Password == "" {
-		return errorPasswordIsRequired
+		errs.Add("password", "is required")
+	} else {
+		for _, problem := range password.Validate(r.User.Password, r.User.Username, r.User.Email) {
+			errs.Add("password", problem)
+		}
 	}
 
-	return nil
+	return errs.Err()
 }
 
 // Register creates new user in the service
-func (s *Service) Register(req *RegisterRequest) (*app.User, error) {
+func (s *Service) Register(ctx context.Context, req *RegisterRequest) (*app.User, error) {
 	// Validate request
 	err := req.Validate()
 	if err != nil {
-		return nil, app.ServiceError(err)
+		return nil, app.ValidationError(err)
 	}
 
 	// Check if user exists
-	u, err := s.store.GetUser(req.User.Email)
+	u, err := s.store.GetUser(ctx, req.User.Email)
 	if err != nil {
 		return nil, app.InternalError(errors.Wrap(err, "failed to get user"))
 	}
 
 	if u != nil {
-		return nil, app.ServiceError(errorUserExists)
+		return nil, app.ConflictError(errorUserExists)
 	}
 
 	// Replace password with hash
@@ -64,13 +74,13 @@ func (s *Service) Register(req *RegisterRequest) (*app.User, error) {
 	}
 
 	// Store new user
-	err = s.store.AddUser(user)
+	err = s.store.AddUser(ctx, user)
 	if err != nil {
 		return nil, app.InternalError(errors.Wrap(err, "failed to add new user"))
 	}
 
 	// Return added user
-	u, err = s.store.GetUser(user.Email)
+	u, err = s.store.GetUser(ctx, user.Email)
 	if err != nil {
 		return nil, app.InternalError(errorUserNotCreated)
 	}
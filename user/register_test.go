@@ -1,13 +1,18 @@
 package user
 
 import (
+	"context"
 	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/dzeban/conduit/app"
 	"github.com/dzeban/conduit/mock"
 )
 
+// testNewPassword satisfies the default password policy
+const testNewPassword = "correct horse battery staple"
+
 func TestRegister(t *testing.T) {
 	// Test cases
 	tests := []struct {
@@ -19,19 +24,23 @@ func TestRegister(t *testing.T) {
 		{
 			"EmptyValidation",
 			&RegisterRequest{},
-			app.ErrorTypeService,
-			nil, // Don't test for specific error because validation order may be changed
+			app.ErrorTypeValidation,
+			app.FieldErrors{
+				"username": {"is required"},
+				"email":    {"is required"},
+				"password": {"is required"},
+			},
 		},
 		{
 			"EmailValidation",
 			&RegisterRequest{
 				RegisterUser{
 					Username: mock.UserValid.Name,
-					Password: mock.TestPassword,
+					Password: testNewPassword,
 				},
 			},
-			app.ErrorTypeService,
-			errorEmailIsRequired,
+			app.ErrorTypeValidation,
+			app.FieldErrors{"email": {"is required"}},
 		},
 		{
 			"PasswordValidation",
@@ -41,19 +50,34 @@ func TestRegister(t *testing.T) {
 					Username: mock.UserValid.Name,
 				},
 			},
-			app.ErrorTypeService,
-			errorPasswordIsRequired,
+			app.ErrorTypeValidation,
+			app.FieldErrors{"password": {"is required"}},
 		},
 		{
 			"UsernameValidation",
 			&RegisterRequest{
 				RegisterUser{
 					Email:    mock.UserValid.Email,
-					Password: mock.TestPassword,
+					Password: testNewPassword,
+				},
+			},
+			app.ErrorTypeValidation,
+			app.FieldErrors{"username": {"is required"}},
+		},
+		{
+			"PasswordPolicy",
+			&RegisterRequest{
+				RegisterUser{
+					Email:    "new@example.com",
+					Username: "newuser",
+					Password: "newuser",
 				},
 			},
-			app.ErrorTypeService,
-			errorUsernameIsRequired,
+			app.ErrorTypeValidation,
+			app.FieldErrors{"password": {
+				"is too short, minimum is 8 characters",
+				"must not contain username or email",
+			}},
 		},
 		{
 			"UserExists",
@@ -61,10 +85,10 @@ func TestRegister(t *testing.T) {
 				RegisterUser{
 					Email:    mock.UserValid.Email,
 					Username: mock.UserValid.Name,
-					Password: mock.TestPassword,
+					Password: testNewPassword,
 				},
 			},
-			app.ErrorTypeService,
+			app.ErrorTypeConflict,
 			errorUserExists,
 		},
 		{
@@ -73,7 +97,7 @@ func TestRegister(t *testing.T) {
 				RegisterUser{
 					Email:    "new@example.com",
 					Username: "new",
-					Password: "new",
+					Password: testNewPassword,
 				},
 			},
 			0,
@@ -85,7 +109,7 @@ func TestRegister(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := s.Register(tt.req)
+			_, err := s.Register(context.Background(), tt.req)
 
 			// Check error
 			if err != nil {
@@ -104,7 +128,7 @@ func TestRegister(t *testing.T) {
 
 				// Check error value
 				if tt.err != nil {
-					if e.Err != tt.err {
+					if !reflect.DeepEqual(e.Err, tt.err) {
 						t.Errorf("Register(%+v): invalid error value: expected %v, got %v", tt.req, tt.err, e.Err)
 						return
 					}
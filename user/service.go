@@ -1,28 +1,40 @@
 package user
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 
 	"github.com/dzeban/conduit/app"
 )
 
 var (
-	errorEmailIsRequired     = errors.New("email is required")
-	errorPasswordIsRequired  = errors.New("password is required")
-	errorPasswordMismatch    = errors.New("password mismatch")
-	errorUsernameIsRequired  = errors.New("username is required")
+	errorInvalidCredentials  = errors.New("invalid email or password")
 	errorUserExists          = errors.New("user exists")
 	errorUserNotFound        = errors.New("user not found")
 	errorUserUpdateForbidden = errors.New("user update forbidden")
 	errorInvalidRequest      = errors.New("invalid request")
 	errorUserNotCreated      = errors.New("user not created")
+	errorRefreshTokenInvalid = errors.New("invalid refresh token")
+	errorRefreshTokenExpired = errors.New("refresh token is expired")
 )
 
 type Store interface {
-	GetUser(email string) (*app.User, error)
-	GetUserById(id int) (*app.User, error)
-	AddUser(user *app.User) error
-	UpdateUser(user *app.User) error
+	GetUser(ctx context.Context, email string) (*app.User, error)
+	GetUserById(ctx context.Context, id int) (*app.User, error)
+	AddUser(ctx context.Context, user *app.User) error
+	UpdateUser(ctx context.Context, user *app.User) error
+
+	// AddRefreshToken stores issued refresh token
+	AddRefreshToken(ctx context.Context, t *app.RefreshToken) error
+
+	// DeleteRefreshToken deletes refresh token by hash and returns it. It
+	// returns nil if there is no such token.
+	DeleteRefreshToken(ctx context.Context, hash string) (*app.RefreshToken, error)
+
+	// DeleteUserRefreshToken deletes refresh token by hash only if it's
+	// issued to the user with id. It returns nil if there is no such token.
+	DeleteUserRefreshToken(ctx context.Context, userId int, hash string) (*app.RefreshToken, error)
 }
 
 // Service provides a service for interacting with user accounts
@@ -36,14 +48,14 @@ func NewService(store Store) *Service {
 }
 
 // Get returns user by email
-func (s *Service) Get(email string) (*app.User, error) {
-	u, err := s.store.GetUser(email)
+func (s *Service) Get(ctx context.Context, email string) (*app.User, error) {
+	u, err := s.store.GetUser(ctx, email)
 	if err != nil {
 		return nil, app.InternalError(errors.Wrap(err, "failed to get user"))
 	}
 
 	if u == nil {
-		return nil, app.ServiceError(errorUserNotFound)
+		return nil, app.NotFoundError(errorUserNotFound)
 	}
 
 	return u, nil
@@ -1,6 +1,8 @@
 package user
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 
 	"github.com/dzeban/conduit/app"
@@ -19,7 +21,10 @@ type UpdateUser struct {
 	Image    string `json:"image,omitempty"`
 }
 
-func (r *UpdateRequest) Validate() error {
+// Validate checks the request and returns app.FieldErrors with all of the
+// problems found. New password is checked against the policy with both new
+// and current identities of the user.
+func (r *UpdateRequest) Validate(current *app.User) error {
 	if r.User.Name == "" &&
 		r.User.Email == "" &&
 		r.User.Bio == "" &&
@@ -28,34 +33,35 @@ func (r *UpdateRequest) Validate() error {
 		return errors.New("at least one of username, email, bio, image, password is required for update")
 	}
 
-	return nil
+	errs := app.FieldErrors{}
+
+	if r.User.Password != "" {
+		for _, problem := range password.Validate(r.User.Password, r.User.Name, r.User.Email, current.Name, current.Email) {
+			errs.Add("password", problem)
+		}
+	}
+
+	return errs.Err()
 }
 
 // Update modifies user found by id with the new data passed in user.
 // It returns updated user.
-func (s *Service) Update(id int, req *UpdateRequest) (*app.User, error) {
-	// Validate request
-	err := req.Validate()
-	if err != nil {
-		return nil, app.ServiceError(err)
-	}
-
+func (s *Service) Update(ctx context.Context, id int, req *UpdateRequest) (*app.User, error) {
 	// Check user exists
-	u, err := s.store.GetUserById(id)
+	u, err := s.store.GetUserById(ctx, id)
 	if err != nil {
 		return nil, app.InternalError(errors.Wrap(err, "failed to get user for update"))
 	}
 
 	if u == nil {
-		return nil, app.ServiceError(errorUserNotFound)
-
+		return nil, app.NotFoundError(errorUserNotFound)
 	}
 
-	// Set fields to update
-	u.Name = req.User.Name
-	u.Email = req.User.Email
-	u.Bio = req.User.Bio
-	u.Image = req.User.Image
+	// Validate request against the current user
+	err = req.Validate(u)
+	if err != nil {
+		return nil, app.ValidationError(err)
+	}
 
 	// If password is being changed, make the hash from it
 	if req.User.Password != "" {
@@ -67,13 +73,19 @@ func (s *Service) Update(id int, req *UpdateRequest) (*app.User, error) {
 		u.PasswordHash = hash
 	}
 
-	err = s.store.UpdateUser(u)
+	// Set fields to update
+	u.Name = req.User.Name
+	u.Email = req.User.Email
+	u.Bio = req.User.Bio
+	u.Image = req.User.Image
+
+	err = s.store.UpdateUser(ctx, u)
 	if err != nil {
 		return nil, app.InternalError(errors.Wrap(err, "failed to update user"))
 	}
 
 	// Return updated user
-	u, err = s.store.GetUserById(id)
+	u, err = s.store.GetUserById(ctx, id)
 	if err != nil {
 		return nil, app.InternalError(errorUserNotFound)
 	}
@@ -1,7 +1,9 @@
 package user
 
 import (
+	"context"
 	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/go-test/deep"
@@ -30,7 +32,7 @@ func TestUpdate(t *testing.T) {
 			"EmptyValidation",
 			mock.UserValid.Id,
 			&UpdateRequest{},
-			app.ErrorTypeService,
+			app.ErrorTypeValidation,
 			nil,
 			nil,
 		},
@@ -38,7 +40,7 @@ func TestUpdate(t *testing.T) {
 			"AbsentUser",
 			-1,
 			&UpdateRequest{UpdateUser{Bio: "blah"}},
-			app.ErrorTypeService,
+			app.ErrorTypeNotFound,
 			nil,
 			nil,
 		},
@@ -60,7 +62,7 @@ func TestUpdate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u, err := s.Update(tt.id, tt.req)
+			u, err := s.Update(context.Background(), tt.id, tt.req)
 			if err != nil {
 				var e app.Error
 				// Unwrap service.Error
@@ -111,9 +113,9 @@ func TestUpdatePassword(t *testing.T) {
 		PasswordHash: hash,
 	}
 
-	_ = store.AddUser(&userUpdatedPassword)
+	_ = store.AddUser(context.Background(), &userUpdatedPassword)
 
-	newPassword := "qwerty"
+	newPassword := testNewPassword
 
 	req := &UpdateRequest{
 		UpdateUser{
@@ -122,7 +124,7 @@ func TestUpdatePassword(t *testing.T) {
 	}
 
 	s := NewService(store)
-	u, err := s.Update(userUpdatedPassword.Id, req)
+	u, err := s.Update(context.Background(), userUpdatedPassword.Id, req)
 	if err != nil {
 		t.Errorf("Update(%v): unexpected error: %v", req, err)
 	}
@@ -138,3 +140,46 @@ func TestUpdatePassword(t *testing.T) {
 		t.Errorf("Update(%v): password wasn't updated", req)
 	}
 }
+
+func TestUpdatePasswordPolicy(t *testing.T) {
+	req := &UpdateRequest{
+		UpdateUser{
+			Password: "Test1234",
+		},
+	}
+
+	s := NewService(mock.NewUserStore())
+	_, err := s.Update(context.Background(), mock.UserValid.Id, req)
+
+	var e app.Error
+	if !errors.As(err, &e) || e.Type != app.ErrorTypeValidation {
+		t.Fatalf("Update(%v): expected validation error, got %v", req, err)
+	}
+
+	expected := app.FieldErrors{"password": {"must not contain username or email"}}
+	if !reflect.DeepEqual(e.Err, expected) {
+		t.Errorf("Update(%v): invalid error value: expected %v, got %v", req, expected, e.Err)
+	}
+}
+
+func TestUpdateRequestValidate(t *testing.T) {
+	req := &UpdateRequest{
+		UpdateUser{
+			Bio:      "bio",
+			Password: "test",
+		},
+	}
+
+	// Short password that contains current username has both problems
+	expected := app.FieldErrors{
+		"password": {
+			"is too short, minimum is 8 characters",
+			"must not contain username or email",
+		},
+	}
+
+	err := req.Validate(&mock.UserValid)
+	if !reflect.DeepEqual(err, expected) {
+		t.Errorf("Validate(%v): expected %v, got %v", req, expected, err)
+	}
+}